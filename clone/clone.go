@@ -1,24 +1,196 @@
 package clone
 
 import (
-	"bytes"
-	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func init() {
-	gob.Register(map[string]interface{}{}) // descriptor.
-	gob.Register([]interface{}{})          // data-package resources.
+// UnsupportedValueError is returned when the descriptor holds a value which is not
+// JSON-compatible, for instance a channel or a map whose keys are not strings.
+type UnsupportedValueError struct {
+	Path string // JSON pointer to the value within the descriptor.
+	Type reflect.Type
 }
 
-// Descriptor deep-copies the passed-in descriptor and returns its copy.
+func (e *UnsupportedValueError) Error() string {
+	return fmt.Sprintf("clone: unsupported value of type %s at %q", e.Type, e.Path)
+}
+
+// Descriptor deep-copies the passed-in descriptor and returns its copy. Values keep their types,
+// which can be any JSON-compatible Go type: nil, booleans, strings, numbers (including json.Number),
+// time.Time, and maps (with string keys), slices, arrays and pointers of those.
 func Descriptor(d map[string]interface{}) (map[string]interface{}, error) {
-	var buf bytes.Buffer
-	if err := gob.NewEncoder(&buf).Encode(d); err != nil {
+	if d == nil {
+		return nil, nil
+	}
+	c, err := copyMap(d)
+	if err != nil {
 		return nil, err
 	}
-	var c map[string]interface{}
-	if err := gob.NewDecoder(&buf).Decode(&c); err != nil {
+	return c, nil
+}
+
+// Value deep-copies the passed-in value and returns its copy. Supported types are the ones
+// supported by Descriptor.
+func Value(v interface{}) (interface{}, error) {
+	return copyValue(v)
+}
+
+func copyValue(v interface{}) (interface{}, error) {
+	// Fast path for the types created when decoding descriptors.
+	switch v := v.(type) {
+	case nil, string, bool, float64, json.Number, int, int64:
+		return v, nil
+	case map[string]interface{}:
+		if v == nil {
+			return v, nil
+		}
+		return copyMap(v)
+	case []interface{}:
+		if v == nil {
+			return v, nil
+		}
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			cv, err := copyValue(val)
+			if err != nil {
+				return nil, withPathIndex(err, i)
+			}
+			c[i] = cv
+		}
+		return c, nil
+	case []string:
+		if v == nil {
+			return v, nil
+		}
+		return append([]string{}, v...), nil
+	case time.Time:
+		return v, nil
+	}
+	c, err := copyReflect(reflect.ValueOf(v))
+	if err != nil {
 		return nil, err
 	}
+	return c.Interface(), nil
+}
+
+func copyMap(m map[string]interface{}) (map[string]interface{}, error) {
+	c := make(map[string]interface{}, len(m))
+	for k, val := range m {
+		cv, err := copyValue(val)
+		if err != nil {
+			return nil, withPath(err, k)
+		}
+		c[k] = cv
+	}
 	return c, nil
 }
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// copyReflect copies the values of the types which are not handled by copyValue fast path.
+func copyReflect(v reflect.Value) (reflect.Value, error) {
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v, nil
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v, nil
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, nil
+		}
+		e, err := copyReflect(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(e)
+		return c, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		e, err := copyReflect(v.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(e)
+		return c, nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return v, nil
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			e, err := copyElem(iter.Value())
+			if err != nil {
+				return reflect.Value{}, withPath(err, iter.Key().String())
+			}
+			c.SetMapIndex(iter.Key(), e)
+		}
+		return c, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return v, nil
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e, err := copyElem(v.Index(i))
+			if err != nil {
+				return reflect.Value{}, withPathIndex(err, i)
+			}
+			c.Index(i).Set(e)
+		}
+		return c, nil
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			e, err := copyElem(v.Index(i))
+			if err != nil {
+				return reflect.Value{}, withPathIndex(err, i)
+			}
+			c.Index(i).Set(e)
+		}
+		return c, nil
+	}
+	return reflect.Value{}, &UnsupportedValueError{Type: v.Type()}
+}
+
+// copyElem copies map and slice elements. Elements of interface types take the fast path.
+func copyElem(v reflect.Value) (reflect.Value, error) {
+	if v.Kind() != reflect.Interface || v.IsNil() {
+		return copyReflect(v)
+	}
+	c, err := copyValue(v.Interface())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	ret := reflect.New(v.Type()).Elem()
+	ret.Set(reflect.ValueOf(c))
+	return ret, nil
+}
+
+func withPath(err error, key string) error {
+	if e, ok := err.(*UnsupportedValueError); ok {
+		e.Path = "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key) + e.Path
+	}
+	return err
+}
+
+func withPathIndex(err error, i int) error {
+	return withPath(err, strconv.Itoa(i))
+}
@@ -1,7 +1,12 @@
 package clone
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 )
@@ -19,9 +24,144 @@ func TestDescriptor(t *testing.T) {
 	is.NoErr(err)
 	is.Equal(d, cpy)
 
-	// Error: Unregistered gob type: map[int]interface{}.
+	// Error: maps must have string keys.
 	_, err = Descriptor(map[string]interface{}{"boo": map[int]interface{}{}})
 	if err == nil {
 		t.Fatal("want:err got:nil")
 	}
 }
+
+func TestDescriptor_Types(t *testing.T) {
+	is := is.New(t)
+	s := "foo"
+	created := time.Date(2021, 11, 25, 10, 11, 24, 0, time.UTC)
+	d := map[string]interface{}{
+		"nil":      nil,
+		"bool":     true,
+		"number":   json.Number("1000000000000000000000"),
+		"float":    1.5,
+		"int":      1,
+		"uint8":    uint8(2),
+		"float32":  float32(2.5),
+		"keywords": []string{"a", "b"},
+		"created":  created,
+		"ptr":      &s,
+		"array":    [2]int{1, 2},
+		"fields":   []map[string]interface{}{{"name": "a"}},
+		"props":    map[string]string{"a": "b"},
+		"nilMap":   map[string]interface{}(nil),
+	}
+	cpy, err := Descriptor(d)
+	is.NoErr(err)
+	is.Equal(d, cpy)
+
+	// Copies do not share their contents.
+	cpy["keywords"].([]string)[0] = "c"
+	cpy["fields"].([]map[string]interface{})[0]["name"] = "b"
+	cpy["props"].(map[string]string)["a"] = "c"
+	*cpy["ptr"].(*string) = "bar"
+	is.Equal(d["keywords"], []string{"a", "b"})
+	is.Equal(d["fields"], []map[string]interface{}{{"name": "a"}})
+	is.Equal(d["props"], map[string]string{"a": "b"})
+	is.Equal(s, "foo")
+}
+
+func TestDescriptor_Unsupported(t *testing.T) {
+	data := []struct {
+		desc string
+		d    map[string]interface{}
+		path string
+	}{
+		{"Chan", map[string]interface{}{"c": make(chan int)}, "/c"},
+		{"Func", map[string]interface{}{"resources": []interface{}{map[string]interface{}{"f": func() {}}}}, "/resources/0/f"},
+		{"Struct", map[string]interface{}{"a/b": []interface{}{struct{}{}}}, "/a~1b/0"},
+		{"IntKeys", map[string]interface{}{"m": []map[int]string{{1: "a"}}}, "/m/0"},
+	}
+	for _, d := range data {
+		t.Run(d.desc, func(t *testing.T) {
+			is := is.New(t)
+			_, err := Descriptor(d.d)
+			e, ok := err.(*UnsupportedValueError)
+			if !ok {
+				t.Fatalf("want:*UnsupportedValueError got:%v", err)
+			}
+			is.Equal(e.Path, d.path)
+		})
+	}
+}
+
+func TestValue(t *testing.T) {
+	is := is.New(t)
+	v := []interface{}{map[string]interface{}{"name": "a"}, []string{"b"}}
+	cpy, err := Value(v)
+	is.NoErr(err)
+	is.Equal(cpy, v)
+	cpy.([]interface{})[0].(map[string]interface{})["name"] = "c"
+	cpy.([]interface{})[1].([]string)[0] = "d"
+	is.Equal(v, []interface{}{map[string]interface{}{"name": "a"}, []string{"b"}})
+
+	if _, err := Value([]interface{}{make(chan int)}); err == nil {
+		t.Fatal("want:err got:nil")
+	}
+}
+
+// gobDescriptor is the previous gob-based implementation, kept to compare performance.
+func gobDescriptor(d map[string]interface{}) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(d); err != nil {
+		return nil, err
+	}
+	var c map[string]interface{}
+	if err := gob.NewDecoder(&buf).Decode(&c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(json.Number(""))
+}
+
+// benchmarkPackage returns a package descriptor with the passed-in number of tabular resources.
+func benchmarkPackage(n int) map[string]interface{} {
+	resources := make([]interface{}, n)
+	for i := range resources {
+		resources[i] = map[string]interface{}{
+			"name":    fmt.Sprintf("res%d", i),
+			"path":    fmt.Sprintf("data/res%d.csv", i),
+			"profile": "tabular-data-resource",
+			"bytes":   json.Number("1024"),
+			"schema": map[string]interface{}{
+				"fields": []interface{}{
+					map[string]interface{}{"name": "id", "type": "integer", "constraints": map[string]interface{}{"required": true}},
+					map[string]interface{}{"name": "name", "type": "string"},
+					map[string]interface{}{"name": "value", "type": "number"},
+				},
+				"primaryKey": []interface{}{"id"},
+			},
+		}
+	}
+	return map[string]interface{}{"name": "pkg", "keywords": []interface{}{"a", "b"}, "resources": resources}
+}
+
+func BenchmarkDescriptor(b *testing.B) {
+	for _, n := range []int{10, 100, 500} {
+		d := benchmarkPackage(n)
+		b.Run(fmt.Sprintf("Resources%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Descriptor(d); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Gob/Resources%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := gobDescriptor(d); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
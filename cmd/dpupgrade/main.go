@@ -0,0 +1,130 @@
+// Command dpupgrade upgrades Data Package descriptors from v1 to v2 in bulk.
+//
+// Usage:
+//
+//	dpupgrade [-w] [-strict] path ...
+//
+// Each path could either be a descriptor file or a directory, which is walked looking
+// for datapackage.json and datapackage.yaml (or .yml) files. Upgraded descriptors are
+// printed to the standard output, unless -w is set, in which case they are written back
+// to their files, keeping their format and property order. Changes are reported to the
+// standard error.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/datapackage"
+)
+
+// Names of the descriptor files looked for when walking directories.
+var descriptorFileNames = map[string]bool{
+	"datapackage.json": true,
+	"datapackage.yaml": true,
+	"datapackage.yml":  true,
+}
+
+var (
+	write  = flag.Bool("w", false, "write upgraded descriptors back to their files instead of printing them")
+	strict = flag.Bool("strict", false, "refuse to upgrade descriptors when information would be lost")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: dpupgrade [-w] [-strict] path ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	failed := false
+	for _, root := range flag.Args() {
+		paths, err := descriptorPaths(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", root, err)
+			failed = true
+			continue
+		}
+		for _, p := range paths {
+			if err := upgrade(p); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", p, err)
+				failed = true
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func descriptorPaths(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+	var paths []string
+	err = filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && descriptorFileNames[info.Name()] {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func upgrade(path string) error {
+	pkg, notes, err := datapackage.LoadUpgraded(path)
+	if err != nil {
+		return err
+	}
+	lossy := false
+	for _, n := range notes {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, n)
+		lossy = lossy || n.Lossy
+	}
+	if lossy && *strict {
+		return fmt.Errorf("upgrade would lose information, skipping")
+	}
+	if !*write {
+		var buf bytes.Buffer
+		if isYAML(path) {
+			err = pkg.WriteYAMLDescriptor(&buf)
+		} else {
+			err = pkg.WriteDescriptor(&buf)
+		}
+		if err != nil {
+			return err
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = buf.WriteTo(os.Stdout)
+		return err
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+	// Saved atomically, keeping the file mode, format, property order and indentation.
+	return pkg.SaveDescriptor(path)
+}
+
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
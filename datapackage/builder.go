@@ -0,0 +1,326 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/frictionlessdata/tableschema-go/schema"
+)
+
+const (
+	csvFormat    = "csv"
+	csvMediaType = "text/csv"
+)
+
+// BuildError aggregates the problems found while building a package.
+type BuildError struct {
+	Errs []error
+}
+
+func (e *BuildError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type builderResource struct {
+	descriptor map[string]interface{}
+	tabular    bool
+}
+
+// Builder builds packages in code, without manually assembling descriptors.
+// Methods can be chained and the package is only validated once, when Build is called.
+//
+//	pkg, err := datapackage.NewBuilder().
+//		Name("cities").
+//		License(datapackage.License{Name: "ODC-PDDL-1.0"}).
+//		AddCSVResource("cities", "cities.csv", &citiesSchema).
+//		WithDialect(datapackage.Dialect{Delimiter: ';', Header: true}).
+//		Build(validator.InMemoryLoader())
+type Builder struct {
+	descriptor map[string]interface{}
+	resources  []*builderResource
+	basePath   string
+	version    string
+	errs       []error
+}
+
+// NewBuilder returns a Builder of a package targeting the v1 specification.
+func NewBuilder() *Builder {
+	return &Builder{descriptor: map[string]interface{}{}, version: SpecV1}
+}
+
+// Spec sets the version of the specification the package targets (SpecV1 or SpecV2).
+func (b *Builder) Spec(version string) *Builder {
+	switch version {
+	case SpecV1, SpecV2:
+		b.version = version
+	default:
+		b.errs = append(b.errs, fmt.Errorf("unsupported specification version:%s", version))
+	}
+	return b
+}
+
+// BasePath sets the path against which relative resource paths are resolved.
+func (b *Builder) BasePath(basePath string) *Builder {
+	b.basePath = basePath
+	return b
+}
+
+// Name sets the package name.
+func (b *Builder) Name(name string) *Builder {
+	return b.set(nameProp, name)
+}
+
+// Title sets the package title.
+func (b *Builder) Title(title string) *Builder {
+	return b.set(titlePropName, title)
+}
+
+// Description sets the package description.
+func (b *Builder) Description(description string) *Builder {
+	return b.set(descriptionPropName, description)
+}
+
+// Version sets the package version.
+func (b *Builder) Version(version string) *Builder {
+	return b.set(versionPropName, version)
+}
+
+// Keywords sets the package keywords.
+func (b *Builder) Keywords(keywords ...string) *Builder {
+	return b.set(keywordsPropName, stringsToList(keywords))
+}
+
+// License adds a license to the package.
+func (b *Builder) License(l License) *Builder {
+	return b.add(licensesPropName, licensesToList([]License{l}))
+}
+
+// Contributor adds a contributor to the package.
+func (b *Builder) Contributor(c Contributor) *Builder {
+	return b.add(contributorsPropName, contributorsToList([]Contributor{c}))
+}
+
+// Source adds a source to the package.
+func (b *Builder) Source(s Source) *Builder {
+	return b.add(sourcesPropName, sourcesToList([]Source{s}))
+}
+
+// Set sets an arbitrary package property.
+func (b *Builder) Set(prop string, value interface{}) *Builder {
+	return b.set(prop, value)
+}
+
+// AddCSVResource adds a resource pointing to a CSV file. The resource is tabular if
+// the schema is not nil.
+func (b *Builder) AddCSVResource(name, path string, sch *schema.Schema) *Builder {
+	return b.addResource(name, sch, map[string]interface{}{
+		pathProp:      path,
+		formatProp:    csvFormat,
+		mediaTypeProp: csvMediaType,
+	})
+}
+
+// AddInlineResource adds a resource holding its data. Data must either be serializable to
+// a JSON array/object or be a CSV string. The resource is tabular if the schema is not nil.
+func (b *Builder) AddInlineResource(name string, data interface{}, sch *schema.Schema) *Builder {
+	d := map[string]interface{}{}
+	if s, ok := data.(string); ok {
+		d[dataProp] = s
+		d[formatProp] = csvFormat
+	} else {
+		v, err := toDescriptorValue(data)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("resource %s: invalid data: %w", name, err))
+			return b
+		}
+		d[dataProp] = v
+	}
+	return b.addResource(name, sch, d)
+}
+
+// WithDialect sets the CSV dialect of the last added resource. Only the properties which
+// are set are written, the others keep their default values: as zero values can not be told
+// apart from unset ones, SkipInitialSpace and DoubleQuote are only written when true and
+// Delimiter when not zero. Header is always written. Use WithResourceProperty to set
+// other dialects (e.g. one which disables doubleQuote).
+func (b *Builder) WithDialect(d Dialect) *Builder {
+	r := b.lastResource("WithDialect")
+	if r == nil {
+		return b
+	}
+	dialect := map[string]interface{}{headerProp: d.Header}
+	if d.Delimiter != 0 {
+		dialect[delimiterProp] = string(d.Delimiter)
+	}
+	if d.SkipInitialSpace {
+		dialect[skipInitialSpaceProp] = true
+	}
+	if d.DoubleQuote {
+		dialect[doubleQuoteProp] = true
+	}
+	r.descriptor[dialectProp] = dialect
+	return b
+}
+
+// WithResourceProperty sets an arbitrary property of the last added resource.
+func (b *Builder) WithResourceProperty(prop string, value interface{}) *Builder {
+	if r := b.lastResource("WithResourceProperty"); r != nil {
+		r.descriptor[prop] = value
+	}
+	return b
+}
+
+// Build validates and returns the package. All problems found are reported together
+// through a *BuildError: the ones found while building, the invalid resources and the
+// package-level ones. Package-level problems are only looked for if at least one
+// resource is valid.
+func (b *Builder) Build(loaders ...validator.RegistryLoader) (*Package, error) {
+	registry, err := validator.NewRegistry(loaders...)
+	if err != nil {
+		return nil, err
+	}
+	loader := func() (validator.Registry, error) { return registry, nil }
+	d, resources := b.build()
+	// Validating resources one by one, so all invalid resources are reported.
+	errs := append([]error{}, b.errs...)
+	var valid []interface{}
+	for _, r := range resources {
+		if _, err := newResource(r, b.basePath, b.version, registry, false); err != nil {
+			errs = append(errs, fmt.Errorf("resource %v: %w", r[nameProp], err))
+			continue
+		}
+		valid = append(valid, r)
+	}
+	// The package is validated without the invalid resources, which are already reported.
+	// As a package needs at least one resource, it is not validated if none is valid: the
+	// errors would only repeat the resource ones.
+	if len(resources) > 0 && len(valid) == 0 {
+		return nil, &BuildError{Errs: errs}
+	}
+	if len(valid) > 0 {
+		d[resourcePropName] = valid
+	}
+	pkg, err := New(d, b.basePath, loader)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, &BuildError{Errs: errs}
+	}
+	return pkg, nil
+}
+
+// build returns the package descriptor and its resources descriptors.
+func (b *Builder) build() (map[string]interface{}, []map[string]interface{}) {
+	d := make(map[string]interface{}, len(b.descriptor)+2)
+	for k, v := range b.descriptor {
+		d[k] = v
+	}
+	if b.version == SpecV2 && d[schemaURLProp] == nil {
+		d[schemaURLProp] = v2PackageSchemaURL
+	}
+	resources := make([]map[string]interface{}, len(b.resources))
+	rSlice := make([]interface{}, len(b.resources))
+	for i, r := range b.resources {
+		rDesc := make(map[string]interface{}, len(r.descriptor)+1)
+		for k, v := range r.descriptor {
+			rDesc[k] = v
+		}
+		if r.tabular {
+			if b.version == SpecV2 {
+				rDesc[typeProp] = tableResourceType
+			} else {
+				rDesc[profilePropName] = tabularDataResourceProfile
+			}
+		}
+		resources[i] = rDesc
+		rSlice[i] = rDesc
+	}
+	d[resourcePropName] = rSlice
+	return d, resources
+}
+
+func (b *Builder) set(prop string, value interface{}) *Builder {
+	setProps(b.descriptor, map[string]interface{}{prop: value})
+	return b
+}
+
+func (b *Builder) add(prop string, values []interface{}) *Builder {
+	l, _ := b.descriptor[prop].([]interface{})
+	b.descriptor[prop] = append(l, values...)
+	return b
+}
+
+func (b *Builder) addResource(name string, sch *schema.Schema, d map[string]interface{}) *Builder {
+	for _, r := range b.resources {
+		if r.descriptor[nameProp] == name {
+			b.errs = append(b.errs, fmt.Errorf("resource %s: duplicated name", name))
+			return b
+		}
+	}
+	d[nameProp] = name
+	r := &builderResource{descriptor: d}
+	if sch != nil {
+		s, err := toDescriptorValue(sch)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("resource %s: invalid schema: %w", name, err))
+			return b
+		}
+		// Unset schema properties are encoded as null (e.g. primaryKey) or as empty objects
+		// (e.g. field constraints).
+		stripEmpty(s)
+		d[schemaProp] = s
+		r.tabular = true
+	}
+	b.resources = append(b.resources, r)
+	return b
+}
+
+// stripEmpty removes the null and empty object properties of the descriptor value, recursively.
+func stripEmpty(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			stripEmpty(child)
+			if m, ok := child.(map[string]interface{}); child == nil || ok && len(m) == 0 {
+				delete(v, k)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			stripEmpty(child)
+		}
+	}
+}
+
+func (b *Builder) lastResource(method string) *builderResource {
+	if len(b.resources) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("%s called before adding a resource", method))
+		return nil
+	}
+	return b.resources[len(b.resources)-1]
+}
+
+// toDescriptorValue converts the passed-in value to its descriptor representation,
+// i.e. the value obtained by decoding its JSON encoding.
+func toDescriptorValue(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Keeping large integers intact. See FromReader.
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	var ret interface{}
+	if err := dec.Decode(&ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
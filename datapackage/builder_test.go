@@ -0,0 +1,113 @@
+package datapackage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/frictionlessdata/tableschema-go/schema"
+	"github.com/matryer/is"
+)
+
+var citiesSchema = schema.Schema{
+	Fields: []schema.Field{
+		{Name: "city", Type: schema.StringType},
+		{Name: "year", Type: schema.IntegerType},
+	},
+}
+
+func TestBuilder(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := NewBuilder().
+			Name("cities").
+			License(License{Name: "ODC-PDDL-1.0"}).
+			AddCSVResource("cities", "cities.csv", &citiesSchema).
+			WithDialect(Dialect{Delimiter: ';', Header: true}).
+			AddInlineResource("inline", "city,year\nlondon,2017", &citiesSchema).
+			AddInlineResource("json", []interface{}{map[string]interface{}{"foo": 1}}, nil).
+			Build(validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.Descriptor()["name"], "cities")
+		is.Equal(pkg.ResourceNames(), []string{"cities", "inline", "json"})
+
+		cities := pkg.GetResource("cities")
+		is.True(cities.Tabular())
+		is.Equal(cities.Format(), "csv")
+		is.Equal(cities.Descriptor()["profile"], "tabular-data-resource")
+		is.Equal(cities.Descriptor()["dialect"], map[string]interface{}{"delimiter": ";", "header": true, "doubleQuote": true})
+		// Unset schema properties are not written.
+		is.Equal(cities.Descriptor()["schema"], map[string]interface{}{"fields": []interface{}{
+			map[string]interface{}{"name": "city", "type": "string"},
+			map[string]interface{}{"name": "year", "type": "integer"},
+		}})
+
+		contents, err := pkg.GetResource("inline").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"city", "year"}, {"london", "2017"}})
+		is.True(!pkg.GetResource("json").Tabular())
+	})
+	t.Run("V2", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := NewBuilder().
+			Spec(SpecV2).
+			AddCSVResource("cities", "cities.csv", &citiesSchema).
+			Build(validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.SpecVersion(), SpecV2)
+		is.Equal(pkg.GetResource("cities").Descriptor()["type"], "table")
+	})
+	t.Run("AllErrorsReported", func(t *testing.T) {
+		is := is.New(t)
+		_, err := NewBuilder().
+			WithDialect(Dialect{}).
+			AddCSVResource("Invalid Name", "foo.csv", nil).
+			AddCSVResource("cities", "cities.csv", nil).
+			AddCSVResource("cities", "cities.csv", nil).
+			Build(validator.InMemoryLoader())
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		var bErr *BuildError
+		is.True(errors.As(err, &bErr))
+		// WithDialect, duplicated name and the invalid resource name.
+		is.Equal(len(bErr.Errs), 3)
+	})
+	t.Run("PackageErrorsReported", func(t *testing.T) {
+		is := is.New(t)
+		_, err := NewBuilder().
+			Name("Invalid Name").
+			AddCSVResource("Invalid Name", "foo.csv", nil).
+			AddCSVResource("cities", "cities.csv", nil).
+			Build(validator.InMemoryLoader())
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		var bErr *BuildError
+		is.True(errors.As(err, &bErr))
+		// The invalid resource and package names.
+		is.Equal(len(bErr.Errs), 2)
+	})
+	t.Run("NoValidResource", func(t *testing.T) {
+		is := is.New(t)
+		_, err := NewBuilder().
+			AddCSVResource("Invalid Name", "foo.csv", nil).
+			Build(validator.InMemoryLoader())
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		var bErr *BuildError
+		is.True(errors.As(err, &bErr))
+		// The resource error is not repeated by the package validation.
+		is.Equal(len(bErr.Errs), 1)
+	})
+	t.Run("InvalidPackage", func(t *testing.T) {
+		_, err := NewBuilder().
+			Name("Invalid Name").
+			AddCSVResource("cities", "cities.csv", nil).
+			Build(validator.InMemoryLoader())
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
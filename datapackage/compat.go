@@ -0,0 +1,177 @@
+package datapackage
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	constraintsProp = "constraints"
+	requiredProp    = "required"
+	anyType         = "any"
+	stringType      = "string"
+)
+
+// Type changes which do not invalidate existing values, besides becoming a string or any.
+var widenedTypes = map[string][]string{
+	"integer": {"number"},
+	"year":    {"integer", "number"},
+}
+
+// ClassifiedChange is a package change, along with the version bump it requires.
+type ClassifiedChange struct {
+	Change
+	Bump   VersionBump
+	Reason string
+}
+
+// Breaking reports whether the change breaks consumers of the previous version.
+func (c ClassifiedChange) Breaking() bool {
+	return c.Bump == BumpMajor
+}
+
+// Compatibility is the result of comparing two versions of a package. Bump is the smallest
+// version increment required by the changes.
+type Compatibility struct {
+	Changes []ClassifiedChange
+	Bump    VersionBump
+}
+
+// Breaking reports whether any change is breaking.
+func (c Compatibility) Breaking() bool {
+	return c.Bump == BumpMajor
+}
+
+// BreakingChanges returns the breaking changes.
+func (c Compatibility) BreakingChanges() []ClassifiedChange {
+	var ret []ClassifiedChange
+	for _, ch := range c.Changes {
+		if ch.Breaking() {
+			ret = append(ret, ch)
+		}
+	}
+	return ret
+}
+
+// CheckCompatibility classifies the changes between two versions of a package, following
+// semantic versioning: removing resources or fields, narrowing field types and adding constraints are
+// breaking (major); adding resources or fields and relaxing constraints are minor; everything
+// else, like metadata and data changes, is a patch. Changes of the version itself are ignored.
+func CheckCompatibility(from, to *Package) Compatibility {
+	var c Compatibility
+	for _, ch := range Diff(from, to) {
+		if ch.IsMetadata() && ch.Property == versionPropName {
+			continue
+		}
+		bump, reason := classifyChange(ch)
+		c.Changes = append(c.Changes, ClassifiedChange{Change: ch, Bump: bump, Reason: reason})
+		if bump > c.Bump {
+			c.Bump = bump
+		}
+	}
+	return c
+}
+
+// SuggestVersion returns the version the new package should have, given the old version.
+func (c Compatibility) SuggestVersion(old SemVer) SemVer {
+	return old.Bump(c.Bump)
+}
+
+func classifyChange(c Change) (VersionBump, string) {
+	switch {
+	case c.IsMetadata():
+		return BumpPatch, "package metadata changed"
+	case c.IsField():
+		return classifyFieldChange(c)
+	case c.Property == "":
+		if c.Kind == Removed {
+			return BumpMajor, "resource removed"
+		}
+		return BumpMinor, "resource added"
+	case c.Property == schemaProp:
+		if c.Kind == Removed {
+			return BumpMinor, "schema removed"
+		}
+		return BumpMajor, "schema added"
+	case c.Property == schemaProp+"."+primaryKeyProp || c.Property == schemaProp+"."+foreignKeysProp:
+		if c.Kind == Removed {
+			return BumpMinor, "key removed"
+		}
+		return BumpMajor, "key added or changed"
+	case c.Property == schemaProp+"."+missingValuesProp:
+		return BumpMinor, "missing values changed"
+	}
+	return BumpPatch, "resource metadata changed"
+}
+
+func classifyFieldChange(c Change) (VersionBump, string) {
+	switch {
+	case c.Property == "" && c.Kind == Removed:
+		return BumpMajor, "field removed"
+	case c.Property == "":
+		if m, ok := c.To.(map[string]interface{}); ok && isRequired(m[constraintsProp]) {
+			return BumpMajor, "required field added"
+		}
+		return BumpMinor, "field added"
+	case c.Property == typeProp:
+		from, to := fieldType(c.From), fieldType(c.To)
+		if typeWidens(from, to) {
+			return BumpMinor, fmt.Sprintf("type widened from %s to %s", from, to)
+		}
+		return BumpMajor, fmt.Sprintf("type narrowed from %s to %s", from, to)
+	case c.Property == formatProp:
+		return BumpMajor, "format changed"
+	case c.Property == constraintsProp:
+		return classifyConstraints(c.From, c.To)
+	}
+	return BumpPatch, "field metadata changed"
+}
+
+func classifyConstraints(from, to interface{}) (VersionBump, string) {
+	old, _ := from.(map[string]interface{})
+	cur, _ := to.(map[string]interface{})
+	if isRequired(cur) && !isRequired(old) {
+		return BumpMajor, "required constraint added"
+	}
+	keys := make([]string, 0, len(cur))
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if k == requiredProp {
+			continue
+		}
+		if ov, ok := old[k]; !ok || !jsonEqual(ov, cur[k]) {
+			return BumpMajor, fmt.Sprintf("%s constraint added or changed", k)
+		}
+	}
+	return BumpMinor, "constraints relaxed"
+}
+
+func isRequired(constraints interface{}) bool {
+	m, _ := constraints.(map[string]interface{})
+	required, _ := m[requiredProp].(bool)
+	return required
+}
+
+// fieldType returns the field type, which defaults to string.
+func fieldType(t interface{}) string {
+	if s, ok := t.(string); ok && s != "" {
+		return s
+	}
+	return stringType
+}
+
+// typeWidens reports whether all values of the from type are valid values of the to type.
+func typeWidens(from, to string) bool {
+	if from == to || to == anyType || to == stringType {
+		return true
+	}
+	for _, t := range widenedTypes[from] {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
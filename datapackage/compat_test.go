@@ -0,0 +1,102 @@
+package datapackage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	const old = `{
+		"name": "pkg",
+		"version": "1.2.3",
+		"resources": [
+			{"name": "res1", "path": "foo.csv", "schema": {"fields": [
+				{"name": "id", "type": "integer"},
+				{"name": "name", "type": "string", "constraints": {"required": true, "maxLength": 10}},
+				{"name": "age", "type": "integer"}
+			]}},
+			{"name": "res2", "path": "bar.csv"}
+		]
+	}`
+	data := []struct {
+		desc    string
+		changed string
+		bump    VersionBump
+		reasons []string
+	}{
+		{"NoChanges", old, BumpNone, nil},
+		{"Metadata", `{"name": "pkg", "title": "Package", "version": "1.2.4", "resources": [
+			{"name": "res1", "path": "foo.csv", "schema": {"fields": [
+				{"name": "id", "type": "integer", "title": "ID"},
+				{"name": "name", "type": "string", "constraints": {"required": true, "maxLength": 10}},
+				{"name": "age", "type": "integer"}
+			]}},
+			{"name": "res2", "path": "baz.csv"}
+		]}`, BumpPatch, []string{"package metadata changed", "field metadata changed", "resource metadata changed"}},
+		{"Widened", `{"name": "pkg", "resources": [
+			{"name": "res1", "path": "foo.csv", "schema": {"fields": [
+				{"name": "id", "type": "number"},
+				{"name": "name", "type": "string", "constraints": {"maxLength": 10}},
+				{"name": "age", "type": "integer"},
+				{"name": "city", "type": "string"}
+			]}},
+			{"name": "res2", "path": "bar.csv"},
+			{"name": "res3", "path": "baz.csv"}
+		]}`, BumpMinor, []string{"resource added", "field added", "type widened from integer to number", "constraints relaxed"}},
+		{"Breaking", `{"name": "pkg", "version": "1.2.3", "resources": [
+			{"name": "res1", "path": "foo.csv", "schema": {"fields": [
+				{"name": "id", "type": "string"},
+				{"name": "name", "type": "date", "constraints": {"required": true, "maxLength": 5}},
+				{"name": "city", "type": "string", "constraints": {"required": true}}
+			], "primaryKey": "id"}}
+		]}`, BumpMajor, []string{"resource removed", "key added or changed", "field removed", "required field added", "type widened from integer to string", "maxLength constraint added or changed", "type narrowed from string to date"}},
+	}
+	for _, d := range data {
+		t.Run(d.desc, func(t *testing.T) {
+			is := is.New(t)
+			from, err := FromString(old, ".", validator.InMemoryLoader())
+			is.NoErr(err)
+			to, err := FromString(d.changed, ".", validator.InMemoryLoader())
+			is.NoErr(err)
+			c := CheckCompatibility(from, to)
+			var reasons []string
+			for _, ch := range c.Changes {
+				reasons = append(reasons, ch.Reason)
+			}
+			is.Equal(reasons, d.reasons)
+			is.Equal(c.Bump, d.bump)
+			is.Equal(c.Breaking(), d.bump == BumpMajor)
+			v, err := from.Version()
+			is.NoErr(err)
+			is.Equal(c.SuggestVersion(v), v.Bump(d.bump))
+		})
+	}
+	t.Run("BreakingChanges", func(t *testing.T) {
+		is := is.New(t)
+		from, err := FromString(old, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		to, err := FromString(`{"name": "pkg", "resources": [{"name": "res2", "path": "bar.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		c := CheckCompatibility(from, to)
+		is.Equal(len(c.BreakingChanges()), 1)
+		is.Equal(c.BreakingChanges()[0].Resource, "res1")
+		is.Equal(c.SuggestVersion(SemVer{Major: 1, Minor: 2, Patch: 3}).String(), "2.0.0")
+	})
+	t.Run("NotWidened", func(t *testing.T) {
+		// Values of the old types are not valid values of the new ones.
+		for _, types := range [][2]string{{"date", "datetime"}, {"object", "geojson"}, {"geopoint", "geojson"}} {
+			is := is.New(t)
+			pkg := `{"name": "pkg", "resources": [{"name": "res1", "path": "foo.csv", "schema": {"fields": [{"name": "a", "type": %q}]}}]}`
+			from, err := FromString(fmt.Sprintf(pkg, types[0]), ".", validator.InMemoryLoader())
+			is.NoErr(err)
+			to, err := FromString(fmt.Sprintf(pkg, types[1]), ".", validator.InMemoryLoader())
+			is.NoErr(err)
+			c := CheckCompatibility(from, to)
+			is.Equal(c.Bump, BumpMajor)
+			is.Equal(c.SuggestVersion(SemVer{Major: 1, PreRelease: "rc.1"}).String(), "1.0.0")
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package datapackage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChangeKind is the kind of a change between two package versions.
+type ChangeKind string
+
+// Kinds of changes.
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// Change is a difference between two versions of a package. Package metadata changes have
+// no Resource, resource changes have no Field and schema field changes have both. Property
+// is the changed property (empty when the whole resource or field was added or removed),
+// schema properties other than fields are prefixed by "schema." (e.g. "schema.primaryKey").
+type Change struct {
+	Kind     ChangeKind
+	Resource string
+	Field    string
+	Property string
+	From, To interface{}
+}
+
+// IsMetadata reports whether the change is a change of the package metadata.
+func (c Change) IsMetadata() bool {
+	return c.Resource == ""
+}
+
+// IsField reports whether the change is a change of a schema field.
+func (c Change) IsField() bool {
+	return c.Field != ""
+}
+
+func (c Change) String() string {
+	var target string
+	switch {
+	case c.IsField():
+		target = fmt.Sprintf("field %s of resource %s", c.Field, c.Resource)
+	case !c.IsMetadata():
+		target = fmt.Sprintf("resource %s", c.Resource)
+	}
+	switch {
+	case c.Property == "":
+		return fmt.Sprintf("%s %s", c.Kind, target)
+	case target == "":
+		return fmt.Sprintf("%s %s", c.Kind, c.Property)
+	}
+	return fmt.Sprintf("%s %s of %s", c.Kind, c.Property, target)
+}
+
+// Diff returns the changes from package a to package b. Changes of the package metadata
+// come first, followed by removed, added and modified resources. Resources and fields
+// are matched by name and properties are reported in alphabetical order.
+func Diff(a, b *Package) []Change {
+	descA, resA := a.snapshot()
+	descB, resB := b.snapshot()
+	changes := diffProps(descA, descB, "", func(c *Change) {}, resourcePropName)
+	var removed, added, modified []Change
+	for _, r := range resA {
+		if findResource(resB, r.name) == nil {
+			removed = append(removed, Change{Kind: Removed, Resource: r.name, From: r.Descriptor()})
+		}
+	}
+	for _, r := range resB {
+		old := findResource(resA, r.name)
+		if old == nil {
+			added = append(added, Change{Kind: Added, Resource: r.name, To: r.Descriptor()})
+			continue
+		}
+		modified = append(modified, diffResources(old.descriptor, r.descriptor, r.name)...)
+	}
+	changes = append(changes, removed...)
+	changes = append(changes, added...)
+	return append(changes, modified...)
+}
+
+func diffResources(a, b map[string]interface{}, name string) []Change {
+	setRes := func(c *Change) { c.Resource = name }
+	changes := diffProps(a, b, "", setRes, schemaProp)
+	schA, _ := a[schemaProp].(map[string]interface{})
+	schB, _ := b[schemaProp].(map[string]interface{})
+	if (schA == nil || schB == nil) && !jsonEqual(a[schemaProp], b[schemaProp]) {
+		kind := Modified
+		switch {
+		case a[schemaProp] == nil:
+			kind = Added
+		case b[schemaProp] == nil:
+			kind = Removed
+		}
+		return append(changes, Change{Kind: kind, Resource: name, Property: schemaProp, From: cloneValue(a[schemaProp]), To: cloneValue(b[schemaProp])})
+	}
+	changes = append(changes, diffProps(schA, schB, schemaProp+".", setRes, fieldsProp)...)
+	return append(changes, diffFields(schA, schB, name)...)
+}
+
+func diffFields(a, b map[string]interface{}, resName string) []Change {
+	fieldsA, fieldsB := schemaFields(a), schemaFields(b)
+	var changes []Change
+	for _, f := range fieldsA.names {
+		if _, ok := fieldsB.byName[f]; !ok {
+			changes = append(changes, Change{Kind: Removed, Resource: resName, Field: f, From: cloneValue(fieldsA.byName[f])})
+		}
+	}
+	for _, f := range fieldsB.names {
+		if _, ok := fieldsA.byName[f]; !ok {
+			changes = append(changes, Change{Kind: Added, Resource: resName, Field: f, To: cloneValue(fieldsB.byName[f])})
+		}
+	}
+	for _, f := range fieldsB.names {
+		if old, ok := fieldsA.byName[f]; ok {
+			changes = append(changes, diffProps(old, fieldsB.byName[f], "", func(c *Change) {
+				c.Resource = resName
+				c.Field = f
+			})...)
+		}
+	}
+	return changes
+}
+
+type namedFields struct {
+	names  []string
+	byName map[string]map[string]interface{}
+}
+
+func schemaFields(sch map[string]interface{}) namedFields {
+	fields := namedFields{byName: map[string]map[string]interface{}{}}
+	l, _ := sch[fieldsProp].([]interface{})
+	for _, f := range l {
+		fMap, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := fMap[nameProp].(string)
+		fields.names = append(fields.names, name)
+		fields.byName[name] = fMap
+	}
+	return fields
+}
+
+// diffProps compares the properties of both objects, ignoring the passed-in ones.
+func diffProps(a, b map[string]interface{}, prefix string, set func(*Change), ignore ...string) []Change {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	for _, k := range ignore {
+		delete(keys, k)
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	var changes []Change
+	for _, k := range sorted {
+		from, inA := a[k]
+		to, inB := b[k]
+		c := Change{Property: prefix + k, From: cloneValue(from), To: cloneValue(to)}
+		switch {
+		case !inA:
+			c.Kind = Added
+		case !inB:
+			c.Kind = Removed
+		case !jsonEqual(from, to):
+			c.Kind = Modified
+		default:
+			continue
+		}
+		set(&c)
+		changes = append(changes, c)
+	}
+	return changes
+}
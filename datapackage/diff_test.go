@@ -0,0 +1,75 @@
+package datapackage
+
+import (
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("Changes", func(t *testing.T) {
+		is := is.New(t)
+		a, err := FromString(`{
+			"name": "pkg",
+			"title": "Package",
+			"resources": [
+				{"name": "res1", "path": "foo.csv", "schema": {"fields": [{"name": "a", "type": "string"}, {"name": "b", "type": "integer"}]}},
+				{"name": "res2", "path": "bar.csv"}
+			]
+		}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		b, err := FromString(`{
+			"name": "pkg",
+			"version": "2.0.0",
+			"resources": [
+				{"name": "res1", "path": "foo.csv", "title": "Foo", "schema": {"fields": [{"name": "a", "type": "integer"}, {"name": "c", "type": "string"}], "primaryKey": "a"}},
+				{"name": "res3", "path": "baz.csv"}
+			]
+		}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		changes := Diff(a, b)
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		is.Equal(got, []string{
+			"removed title",
+			"added version",
+			"removed resource res2",
+			"added resource res3",
+			"added title of resource res1",
+			"added schema.primaryKey of resource res1",
+			"removed field b of resource res1",
+			"added field c of resource res1",
+			"modified type of field a of resource res1",
+		})
+		is.True(changes[0].IsMetadata())
+		is.Equal(changes[0].From, "Package")
+		is.True(!changes[4].IsMetadata() && !changes[4].IsField())
+		is.Equal(changes[8], Change{Kind: Modified, Resource: "res1", Field: "a", Property: "type", From: "string", To: "integer"})
+
+		// Changes are copies.
+		changes[2].From.(map[string]interface{})["name"] = "foo"
+		is.True(a.GetResource("res2") != nil)
+	})
+	t.Run("NoChanges", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"resources": [{"name": "res1", "path": "foo.csv", "bytes": 10}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		other, err := New(pkg.Descriptor(), ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(len(Diff(pkg, other)), 0)
+	})
+	t.Run("Schema", func(t *testing.T) {
+		is := is.New(t)
+		a, err := FromString(`{"resources": [{"name": "res1", "path": "foo.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		b, err := FromString(`{"resources": [{"name": "res1", "path": "foo.csv", "schema": {"fields": [{"name": "a"}]}}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		changes := Diff(a, b)
+		is.Equal(len(changes), 1)
+		is.Equal(changes[0].String(), "added schema of resource res1")
+		is.Equal(Diff(b, a)[0].Kind, Removed)
+	})
+}
@@ -0,0 +1,364 @@
+package datapackage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+	"github.com/frictionlessdata/datapackage-go/validator"
+)
+
+const (
+	defaultFetchWorkers = 4
+	defaultFetchBackoff = 500 * time.Millisecond
+	// Directory, within the fetch directory, where resources referenced by URLs are saved.
+	fetchDataDir = "data"
+)
+
+// FetchOption configures how packages are fetched.
+type FetchOption func(*fetchOptions)
+
+type fetchOptions struct {
+	workers  int
+	retries  int
+	backoff  time.Duration
+	client   *http.Client
+	progress func(FetchEvent)
+}
+
+// FetchWorkers sets the maximum number of files fetched concurrently. Defaults to 4.
+func FetchWorkers(n int) FetchOption {
+	return func(o *fetchOptions) {
+		o.workers = n
+	}
+}
+
+// FetchRetries sets how many times failed downloads are retried. The delay before the first retry is
+// backoff, doubling before each of the following ones. Only network errors and server errors
+// (HTTP 5xx and 429) are retried. Downloads are not retried by default.
+func FetchRetries(n int, backoff time.Duration) FetchOption {
+	return func(o *fetchOptions) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// FetchHTTPClient sets the HTTP client used to download remote files. Defaults to http.DefaultClient.
+func FetchHTTPClient(c *http.Client) FetchOption {
+	return func(o *fetchOptions) {
+		o.client = c
+	}
+}
+
+// FetchProgress sets a function called each time a file is fetched (or fails to be fetched).
+// Calls are never concurrent.
+func FetchProgress(f func(FetchEvent)) FetchOption {
+	return func(o *fetchOptions) {
+		o.progress = f
+	}
+}
+
+// FetchEvent reports the progress of Package.Fetch.
+type FetchEvent struct {
+	Resource string // Name of the resource the file belongs to.
+	Source   string // URL or local path the file was fetched from.
+	Path     string // Path of the fetched file, relative to the fetch directory.
+	Bytes    int64  // Size of the fetched file.
+	Done     int    // Number of files already fetched, including this one.
+	Total    int    // Number of files to fetch.
+	Err      error  // Error fetching the file, if any.
+}
+
+// fetchStatusError is returned when a remote file could not be downloaded.
+type fetchStatusError struct {
+	url  string
+	code int
+}
+
+func (e *fetchStatusError) Error() string {
+	return fmt.Sprintf("error downloading %s: %d %s", e.url, e.code, http.StatusText(e.code))
+}
+
+// fetchNetError is returned when a remote file could not be downloaded because of a network
+// failure: the request failed or the connection broke while reading the response body.
+type fetchNetError struct {
+	err error
+}
+
+func (e *fetchNetError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fetchNetError) Unwrap() error {
+	return e.err
+}
+
+// bodyReader records the errors reading the response body, so they can be told apart from
+// the ones writing the file.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+type fetchJob struct {
+	resource string
+	src      string
+	dst      string // Relative to the fetch directory, slash-separated.
+	remote   bool
+}
+
+// Fetch mirrors the package into the passed-in directory, so it can be processed offline. All
+// resource files (multipart included) are fetched concurrently: remote ones are downloaded and local
+// ones are copied. Files referenced by relative paths keep their paths, while files referenced by
+// URLs are saved in the data directory and the descriptor is changed to point at them.
+//
+// Once all files are fetched, the package is changed to use the directory as its base path
+// and its descriptor is saved there as datapackage.json, with schemas and dialects inline. The package
+// is not changed if any file fails to be fetched or the package was changed meanwhile.
+func (p *Package) Fetch(ctx context.Context, dir string, opts ...FetchOption) error {
+	o := fetchOptions{workers: defaultFetchWorkers, backoff: defaultFetchBackoff, client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.workers < 1 {
+		o.workers = 1
+	}
+	p.mu.RLock()
+	d, resources, reg := p.descriptor, p.resources, p.valRegistry
+	p.mu.RUnlock()
+
+	jobs, paths, err := fetchJobs(resources)
+	if err != nil {
+		return err
+	}
+	if err := runFetchJobs(ctx, dir, jobs, o); err != nil {
+		return err
+	}
+
+	// Pointing the descriptor at the local copies.
+	rCopy, err := clone.Value(d[resourcePropName])
+	if err != nil {
+		return err
+	}
+	newDesc := withProp(d, resourcePropName, rCopy)
+	rSlice, _ := newDesc[resourcePropName].([]interface{})
+	for i, newPaths := range paths {
+		if rMap, ok := rSlice[i].(map[string]interface{}); ok && newPaths != nil {
+			setPaths(rMap, newPaths)
+		}
+	}
+	newP, err := New(newDesc, dir, func() (validator.Registry, error) { return reg, nil })
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if reflect.ValueOf(p.descriptor).Pointer() != reflect.ValueOf(d).Pointer() {
+		return fmt.Errorf("package changed while being fetched")
+	}
+	// Schemas and dialects are inline, they have not been fetched.
+	keepResourceDefaults(newP.resources, resources)
+	p.refs = nil
+	p.basePath = newP.basePath
+	p.descriptor = newP.descriptor
+	p.valRegistry = newP.valRegistry
+	p.setResources(newP.resources)
+	return p.saveDescriptor(filepath.Join(dir, descriptorFileNameWithinZip), InlineReferences())
+}
+
+// fetchJobs returns the files to fetch and the new paths of the resources which have their paths changed.
+func fetchJobs(resources []*Resource) ([]fetchJob, [][]string, error) {
+	var jobs []fetchJob
+	paths := make([][]string, len(resources))
+	byDst := make(map[string]string)
+	for i, r := range resources {
+		changed := false
+		newPaths := make([]string, len(r.path))
+		for j, p := range r.path {
+			job := fetchJob{resource: r.name, src: joinPaths(r.basePath, p), dst: p}
+			if t, _ := checkPath(p); t == urlPath {
+				job.src = p
+				job.dst = urlLocalPath(r.name, i, j, len(r.path) > 1, p)
+				changed = true
+			}
+			_, job.remote = parseRemotePath(job.src)
+			newPaths[j] = job.dst
+			if src, ok := byDst[job.dst]; ok {
+				if src != job.src {
+					return nil, nil, fmt.Errorf("resource %s: %s and %s would be fetched to the same path (%s)", r.name, src, job.src, job.dst)
+				}
+				continue
+			}
+			byDst[job.dst] = job.src
+			jobs = append(jobs, job)
+		}
+		if changed {
+			paths[i] = newPaths
+		}
+	}
+	return jobs, paths, nil
+}
+
+// setPaths sets the resource paths, keeping the descriptor path a string if it was one.
+func setPaths(rMap map[string]interface{}, paths []string) {
+	if _, ok := rMap[pathProp].(string); ok && len(paths) == 1 {
+		rMap[pathProp] = paths[0]
+	} else {
+		rMap[pathProp] = stringsToList(paths)
+	}
+}
+
+// resourceFileName returns the resource name, if it is safe to use it as file name.
+func resourceFileName(resource string, index int) string {
+	if resource == "" || resource == "." || resource == ".." || path.Base(resource) != resource || filepath.Base(resource) != resource {
+		return fmt.Sprintf("resource%d", index)
+	}
+	return resource
+}
+
+// urlLocalPath returns the path a file referenced by the URL is saved to.
+func urlLocalPath(resource string, index, part int, multipart bool, u string) string {
+	dir := resourceFileName(resource, index)
+	name := "data"
+	if parsed, err := url.Parse(u); err == nil {
+		if b := path.Base(parsed.Path); b != "/" && b != "." {
+			name = b
+		}
+	}
+	if multipart {
+		name = fmt.Sprintf("%d-%s", part, name)
+	}
+	return path.Join(fetchDataDir, dir, name)
+}
+
+func runFetchJobs(ctx context.Context, dir string, jobs []fetchJob, o fetchOptions) error {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+		queue    = make(chan fetchJob)
+	)
+	for w := 0; w < o.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				n, err := fetchFile(ctx, dir, job, o)
+				mu.Lock()
+				done++
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("resource %s: %w", job.resource, err)
+					cancel()
+				}
+				if o.progress != nil {
+					o.progress(FetchEvent{Resource: job.resource, Source: job.src, Path: job.dst, Bytes: n, Done: done, Total: len(jobs), Err: err})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return parent.Err()
+}
+
+// fetchFile fetches the file, retrying failed downloads.
+func fetchFile(ctx context.Context, dir string, job fetchJob, o fetchOptions) (int64, error) {
+	dst := filepath.Join(dir, filepath.FromSlash(job.dst))
+	if !job.remote {
+		f, err := os.Open(job.src)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		return writeFile(dst, f)
+	}
+	for attempt := 0; ; attempt++ {
+		n, err := download(ctx, o.client, job.src, dst)
+		if err == nil || attempt >= o.retries || !retryable(err) || ctx.Err() != nil {
+			return n, err
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(o.backoff << attempt):
+		}
+	}
+}
+
+func download(ctx context.Context, client *http.Client, src, dst string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, &fetchNetError{err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, &fetchStatusError{url: src, code: resp.StatusCode}
+	}
+	body := &bodyReader{r: resp.Body}
+	n, err := writeFile(dst, body)
+	// Failures reading the body (e.g. io.ErrUnexpectedEOF, when the connection is dropped) are
+	// network ones, unlike the ones writing the file.
+	if err != nil && body.err != nil {
+		return 0, &fetchNetError{err: err}
+	}
+	return n, err
+}
+
+// retryable reports whether the download failure is worth retrying: network failures (see
+// fetchNetError) and server-side ones. Local failures (e.g. writing the file) are not.
+func retryable(err error) bool {
+	var statusErr *fetchStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= 500 || statusErr.code == http.StatusTooManyRequests
+	}
+	var netErr *fetchNetError
+	return errors.As(err, &netErr)
+}
+
+// writeFile writes the contents to the file, which is only created if all contents could be read.
+func writeFile(dst string, r io.Reader) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return 0, err
+	}
+	return writeFileAtomic(dst, r, defaultFileMode)
+}
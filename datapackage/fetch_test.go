@@ -0,0 +1,227 @@
+package datapackage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestPackage_Fetch(t *testing.T) {
+	var (
+		mu              sync.Mutex
+		inFlight, peak  int
+		flakyCalls      int
+		truncatedCalls  int
+		contents        = map[string]string{"/pkg/data.csv": "name\nfoo", "/files/part1.csv": "name\nbar", "/files/part2.csv": "\nbaz", "/other.csv": "name\nqux"}
+		failuresToServe = 2
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+		if r.URL.Path == "/flaky.csv" {
+			mu.Lock()
+			flakyCalls++
+			calls := flakyCalls
+			mu.Unlock()
+			if calls <= failuresToServe {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "name\nflaky")
+			return
+		}
+		if r.URL.Path == "/truncated.csv" {
+			mu.Lock()
+			truncatedCalls++
+			mu.Unlock()
+			// Dropping the connection in the middle of the body.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(conn, "HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\nname\ntrun")
+			conn.Close()
+			return
+		}
+		c, ok := contents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, c)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	descriptor := fmt.Sprintf(`{"resources": [
+		{"name": "relative", "path": "data.csv", "profile": "tabular-data-resource", "schema": {"fields": [{"name": "name", "type": "string"}]}},
+		{"name": "multipart", "path": ["%[1]s/files/part1.csv", "%[1]s/files/part2.csv"], "profile": "tabular-data-resource", "schema": {"fields": [{"name": "name", "type": "string"}]}},
+		{"name": "url", "path": "%[1]s/other.csv"},
+		{"name": "inline", "data": [{"name": "foo"}]}
+	]}`, ts.URL)
+
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		pkg, err := FromString(descriptor, ts.URL+"/pkg", validator.InMemoryLoader())
+		is.NoErr(err)
+		var events []FetchEvent
+		is.NoErr(pkg.Fetch(context.Background(), dir, FetchWorkers(2), FetchProgress(func(e FetchEvent) {
+			events = append(events, e)
+		})))
+		is.Equal(len(events), 4)
+		for _, e := range events {
+			is.NoErr(e.Err)
+			is.Equal(e.Total, 4)
+		}
+		is.Equal(events[3].Done, 4)
+		mu.Lock()
+		is.True(peak <= 2)
+		mu.Unlock()
+
+		// The package now points at the local copies.
+		is.Equal(pkg.GetResource("relative").Path(), []string{"data.csv"})
+		is.Equal(pkg.GetResource("multipart").Path(), []string{"data/multipart/0-part1.csv", "data/multipart/1-part2.csv"})
+		is.Equal(pkg.GetResource("url").Descriptor()["path"], "data/url/other.csv")
+		contents, err := pkg.GetResource("multipart").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"name"}, {"bar"}, {"baz"}})
+
+		// The mirrored package can be loaded.
+		loaded, err := Load(filepath.Join(dir, "datapackage.json"), validator.InMemoryLoader())
+		is.NoErr(err)
+		contents, err = loaded.GetResource("relative").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"name"}, {"foo"}})
+	})
+	t.Run("Local", func(t *testing.T) {
+		is := is.New(t)
+		src, err := ioutil.TempDir("", "datapackage_fetch_src")
+		is.NoErr(err)
+		defer os.RemoveAll(src)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		is.NoErr(os.MkdirAll(filepath.Join(src, "data"), os.ModePerm))
+		is.NoErr(ioutil.WriteFile(filepath.Join(src, "data", "foo.csv"), []byte("foo"), 0666))
+		pkg, err := FromString(`{"resources": [{"name": "res1", "path": "data/foo.csv"}, {"name": "res2", "path": "data/foo.csv"}]}`, src, validator.InMemoryLoader())
+		is.NoErr(err)
+		is.NoErr(pkg.Fetch(context.Background(), dir))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "data", "foo.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "foo")
+	})
+	t.Run("Retries", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		d := fmt.Sprintf(`{"resources": [{"name": "flaky", "path": "%s/flaky.csv"}]}`, ts.URL)
+
+		mu.Lock()
+		flakyCalls = 0
+		mu.Unlock()
+		pkg, err := FromString(d, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		if err := pkg.Fetch(context.Background(), dir, FetchRetries(1, time.Millisecond)); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(pkg.GetResource("flaky").Path()[0], ts.URL+"/flaky.csv")
+
+		mu.Lock()
+		flakyCalls = 0
+		mu.Unlock()
+		is.NoErr(pkg.Fetch(context.Background(), dir, FetchRetries(2, time.Millisecond)))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "data", "flaky", "flaky.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "name\nflaky")
+	})
+	t.Run("TruncatedBodyRetried", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		pkg, err := FromString(fmt.Sprintf(`{"resources": [{"name": "res", "path": "%s/truncated.csv"}]}`, ts.URL), ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		if err := pkg.Fetch(context.Background(), dir, FetchRetries(3, time.Millisecond)); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		mu.Lock()
+		is.True(truncatedCalls > 1)
+		mu.Unlock()
+	})
+	t.Run("WriteErrorNotRetried", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		// Files can not be written into data, which is not a directory.
+		is.NoErr(ioutil.WriteFile(filepath.Join(dir, "data"), nil, 0666))
+		pkg, err := FromString(fmt.Sprintf(`{"resources": [{"name": "res", "path": "%s/other.csv"}]}`, ts.URL), ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		var events []FetchEvent
+		err = pkg.Fetch(context.Background(), dir, FetchRetries(3, time.Hour), FetchProgress(func(e FetchEvent) {
+			events = append(events, e)
+		}))
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(len(events), 1)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		pkg, err := FromString(fmt.Sprintf(`{"resources": [{"name": "res", "path": "%s/foo.csv"}]}`, ts.URL), ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		var events []FetchEvent
+		err = pkg.Fetch(context.Background(), dir, FetchRetries(3, time.Hour), FetchProgress(func(e FetchEvent) {
+			events = append(events, e)
+		}))
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(len(events), 1)
+		is.True(events[0].Err != nil)
+		_, err = os.Stat(filepath.Join(dir, "datapackage.json"))
+		is.True(os.IsNotExist(err))
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_fetch")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		pkg, err := FromString(descriptor, ts.URL+"/pkg", validator.InMemoryLoader())
+		is.NoErr(err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := pkg.Fetch(ctx, dir); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(pkg.GetResource("url").Descriptor()["path"], ts.URL+"/other.csv")
+	})
+}
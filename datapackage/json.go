@@ -0,0 +1,54 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the package descriptor, implementing json.Marshaler.
+func (p *Package) MarshalJSON() ([]byte, error) {
+	d, _ := p.snapshot()
+	return json.Marshal(d)
+}
+
+// UnmarshalJSON decodes and validates the package descriptor, implementing json.Unmarshaler.
+// Packages previously created keep their base path and validator registry, while zero value
+// packages use the default registry and resolve relative paths against the current directory.
+// Large integers are kept intact, as done by FromReader.
+func (p *Package) UnmarshalJSON(b []byte) error {
+	d, err := decodeDescriptor(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.valRegistry == nil {
+		return p.updateWith(d)
+	}
+	return p.update(d)
+}
+
+// MarshalJSON encodes the resource descriptor, implementing json.Marshaler.
+func (r *Resource) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.descriptor)
+}
+
+// UnmarshalJSON decodes and validates the resource descriptor, implementing json.Unmarshaler.
+// Resources previously created keep their base path and validator registry, while zero value
+// resources use the default registry. Unmarshalling a resource of a package does not change
+// the package. Large integers are kept intact, as done by FromReader.
+func (r *Resource) UnmarshalJSON(b []byte) error {
+	d, err := decodeDescriptor(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	if r.registry == nil {
+		return r.Update(d)
+	}
+	res, err := newResource(d, r.basePath, r.SpecVersion(), r.registry, r.pkg == nil)
+	if err != nil {
+		return err
+	}
+	*r = *res
+	return nil
+}
@@ -0,0 +1,57 @@
+package datapackage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestPackage_JSON(t *testing.T) {
+	type config struct {
+		Pkg *Package `json:"package"`
+	}
+	t.Run("RoundTrip", func(t *testing.T) {
+		is := is.New(t)
+		var c config
+		is.NoErr(json.Unmarshal([]byte(`{"package": {"name": "pkg", "resources": [{"name": "res", "path": "foo.csv", "bytes": 1000000000000000000000}]}}`), &c))
+		is.Equal(c.Pkg.ResourceNames(), []string{"res"})
+		is.Equal(c.Pkg.GetResource("res").Descriptor()["bytes"], json.Number("1000000000000000000000"))
+
+		buf, err := json.Marshal(c)
+		is.NoErr(err)
+		var got map[string]interface{}
+		is.NoErr(json.Unmarshal(buf, &got))
+		is.Equal(got["package"].(map[string]interface{})["name"], "pkg")
+		is.Equal(got["package"].(map[string]interface{})["profile"], "data-package")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var c config
+		if err := json.Unmarshal([]byte(`{"package": {"name": "pkg"}}`), &c); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := json.Unmarshal([]byte(`{"package": 1}`), &c); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
+
+func TestResource_JSON(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		is := is.New(t)
+		var r Resource
+		is.NoErr(json.Unmarshal([]byte(`{"name": "res", "path": "foo.csv", "bytes": 1000000000000000000000}`), &r))
+		is.Equal(r.Name(), "res")
+		is.Equal(r.Descriptor()["bytes"], json.Number("1000000000000000000000"))
+
+		buf, err := json.Marshal(&r)
+		is.NoErr(err)
+		is.Equal(string(buf), `{"bytes":1000000000000000000000,"encoding":"utf-8","name":"res","path":"foo.csv","profile":"data-resource"}`)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var r Resource
+		if err := json.Unmarshal([]byte(`{"name": "res"}`), &r); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
@@ -0,0 +1,495 @@
+package datapackage
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+	"github.com/frictionlessdata/datapackage-go/validator"
+)
+
+const (
+	idPropName          = "id"
+	titlePropName       = "title"
+	descriptionPropName = "description"
+	homepagePropName    = "homepage"
+	versionPropName     = "version"
+	createdPropName     = "created"
+	keywordsPropName    = "keywords"
+	imagePropName       = "image"
+)
+
+// License describes a license under which a package or resource is published. Licenses
+// given as strings (a legacy form) are parsed into Name and written back as strings,
+// unless they are changed.
+// https://specs.frictionlessdata.io/data-package/#licenses
+type License struct {
+	// Name is an Open Definition license ID.
+	Name string
+	// Path is a URL or path pointing to the license text.
+	Path  string
+	Title string
+	// Extra holds properties not defined by the specification, as well as the ones whose values
+	// can not be represented by the fields above, so they survive round-trips.
+	Extra map[string]interface{}
+
+	raw interface{} // Original value, if it is not an object.
+}
+
+// Contributor describes a person or organisation which contributed to the package.
+// Contributors given as strings are parsed into Title and written back as strings,
+// unless they are changed.
+// https://specs.frictionlessdata.io/data-package/#contributors
+type Contributor struct {
+	Title        string
+	GivenName    string
+	FamilyName   string
+	Path         string
+	Email        string
+	Organization string
+	// Role is the v1 contributor role (e.g. "author").
+	Role string
+	// Roles are the v2 contributor roles.
+	Roles []string
+	// Extra holds properties not defined by the specification, as well as the ones whose values
+	// can not be represented by the fields above, so they survive round-trips.
+	Extra map[string]interface{}
+
+	raw interface{} // Original value, if it is not an object.
+}
+
+// Source describes a raw source of the data. Sources given as strings are parsed into Title
+// and written back as strings, unless they are changed.
+// https://specs.frictionlessdata.io/data-package/#sources
+type Source struct {
+	Title   string
+	Path    string
+	Email   string
+	Version string
+	// Extra holds properties not defined by the specification, as well as the ones whose values
+	// can not be represented by the fields above, so they survive round-trips.
+	Extra map[string]interface{}
+
+	raw interface{} // Original value, if it is not an object.
+}
+
+// Metadata holds the package metadata properties.
+// https://specs.frictionlessdata.io/data-package/#metadata
+type Metadata struct {
+	Name         string
+	ID           string
+	Title        string
+	Description  string
+	Homepage     string
+	Version      string
+	Image        string
+	Created      time.Time
+	Keywords     []string
+	Licenses     []License
+	Contributors []Contributor
+	Sources      []Source
+}
+
+// Metadata returns the typed package metadata. It returns an error if a metadata property
+// can not be parsed, for instance a created property which is not a RFC3339 date-time.
+func (p *Package) Metadata() (Metadata, error) {
+	d, _ := p.snapshot()
+	return parseMetadata(d)
+}
+
+func parseMetadata(d map[string]interface{}) (Metadata, error) {
+	m := Metadata{
+		Name:        stringProp(d, nameProp),
+		ID:          stringProp(d, idPropName),
+		Title:       stringProp(d, titlePropName),
+		Description: stringProp(d, descriptionPropName),
+		Homepage:    stringProp(d, homepagePropName),
+		Version:     stringProp(d, versionPropName),
+		Image:       stringProp(d, imagePropName),
+		Keywords:    stringsProp(d, keywordsPropName),
+	}
+	if c, ok := d[createdPropName].(string); ok {
+		t, err := time.Parse(time.RFC3339, c)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("invalid created property:%w", err)
+		}
+		m.Created = t
+	}
+	var err error
+	if m.Licenses, err = parseLicenses(d[licensesPropName]); err != nil {
+		return Metadata{}, err
+	}
+	if m.Sources, err = parseSources(d[sourcesPropName]); err != nil {
+		return Metadata{}, err
+	}
+	if m.Contributors, err = parseContributors(d[contributorsPropName]); err != nil {
+		return Metadata{}, err
+	}
+	return m, nil
+}
+
+// SetMetadata replaces the package metadata properties. Zero values remove their respective
+// properties and properties which are not metadata (e.g. resources) are kept as is, as well
+// as the properties which are not changed: values which can not be represented by Metadata
+// (e.g. keywords which are not strings) survive round-trips.
+// The package is only updated if the resulting descriptor is valid, otherwise the error will be returned.
+func (p *Package) SetMetadata(m Metadata) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	props := metadataProps(m)
+	if cur, err := parseMetadata(p.descriptor); err == nil {
+		for k, v := range metadataProps(cur) {
+			if reflect.DeepEqual(props[k], v) {
+				delete(props, k)
+			}
+		}
+	}
+	return p.updateProperties(props)
+}
+
+// metadataProps returns the descriptor properties holding the metadata.
+func metadataProps(m Metadata) map[string]interface{} {
+	return map[string]interface{}{
+		nameProp:             m.Name,
+		idPropName:           m.ID,
+		titlePropName:        m.Title,
+		descriptionPropName:  m.Description,
+		homepagePropName:     m.Homepage,
+		versionPropName:      m.Version,
+		imagePropName:        m.Image,
+		createdPropName:      formatTime(m.Created),
+		keywordsPropName:     stringsToList(m.Keywords),
+		licensesPropName:     licensesToList(m.Licenses),
+		sourcesPropName:      sourcesToList(m.Sources),
+		contributorsPropName: contributorsToList(m.Contributors),
+	}
+}
+
+// SetName sets the package name. An empty name removes the property.
+func (p *Package) SetName(name string) error {
+	return p.setProperties(map[string]interface{}{nameProp: name})
+}
+
+// SetTitle sets the package title. An empty title removes the property.
+func (p *Package) SetTitle(title string) error {
+	return p.setProperties(map[string]interface{}{titlePropName: title})
+}
+
+// SetDescription sets the package description. An empty description removes the property.
+func (p *Package) SetDescription(description string) error {
+	return p.setProperties(map[string]interface{}{descriptionPropName: description})
+}
+
+// SetVersion sets the package version. An empty version removes the property.
+func (p *Package) SetVersion(version string) error {
+	return p.setProperties(map[string]interface{}{versionPropName: version})
+}
+
+// SetCreated sets the package creation date-time. A zero time removes the property.
+func (p *Package) SetCreated(created time.Time) error {
+	return p.setProperties(map[string]interface{}{createdPropName: formatTime(created)})
+}
+
+// SetKeywords sets the package keywords. An empty list removes the property.
+func (p *Package) SetKeywords(keywords ...string) error {
+	return p.setProperties(map[string]interface{}{keywordsPropName: stringsToList(keywords)})
+}
+
+// SetLicenses sets the package licenses. An empty list removes the property.
+func (p *Package) SetLicenses(licenses ...License) error {
+	return p.setProperties(map[string]interface{}{licensesPropName: licensesToList(licenses)})
+}
+
+// SetContributors sets the package contributors. An empty list removes the property.
+func (p *Package) SetContributors(contributors ...Contributor) error {
+	return p.setProperties(map[string]interface{}{contributorsPropName: contributorsToList(contributors)})
+}
+
+// SetSources sets the package sources. An empty list removes the property.
+func (p *Package) SetSources(sources ...Source) error {
+	return p.setProperties(map[string]interface{}{sourcesPropName: sourcesToList(sources)})
+}
+
+// setProperties sets the passed-in descriptor properties, removing the ones with empty values.
+// The package is validated against the registry it was created with.
+func (p *Package) setProperties(props map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.updateProperties(props)
+}
+
+// updateProperties is setProperties, p.mu must be held.
+func (p *Package) updateProperties(props map[string]interface{}) error {
+	d, err := clone.Descriptor(p.descriptor)
+	if err != nil {
+		return err
+	}
+	setProps(d, props)
+	return p.update(d)
+}
+
+// update replaces the package with a new one created from the passed-in descriptor, reusing
+// the package validator registry. p.mu must be held.
+func (p *Package) update(d map[string]interface{}) error {
+	reg := p.valRegistry
+	return p.updateWith(d, func() (validator.Registry, error) { return reg, nil })
+}
+
+func setProps(d map[string]interface{}, props map[string]interface{}) {
+	for k, v := range props {
+		if isEmptyProp(v) {
+			delete(d, k)
+			continue
+		}
+		d[k] = v
+	}
+}
+
+func isEmptyProp(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func parseLicenses(i interface{}) ([]License, error) {
+	var ret []License
+	for _, v := range list(i) {
+		l, err := parseLicense(v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, l)
+	}
+	return ret, nil
+}
+
+func parseLicense(v interface{}) (License, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		s, _ := v.(string)
+		return License{Name: s, raw: v}, nil
+	}
+	cpy, err := clone.Descriptor(m)
+	if err != nil {
+		return License{}, err
+	}
+	return License{
+		Name:  popString(cpy, nameProp),
+		Path:  popString(cpy, pathProp),
+		Title: popString(cpy, titlePropName),
+		Extra: extra(cpy),
+	}, nil
+}
+
+func parseSources(i interface{}) ([]Source, error) {
+	var ret []Source
+	for _, v := range list(i) {
+		s, err := parseSource(v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func parseSource(v interface{}) (Source, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		s, _ := v.(string)
+		return Source{Title: s, raw: v}, nil
+	}
+	cpy, err := clone.Descriptor(m)
+	if err != nil {
+		return Source{}, err
+	}
+	return Source{
+		Title:   popString(cpy, titlePropName),
+		Path:    popString(cpy, pathProp),
+		Email:   popString(cpy, "email"),
+		Version: popString(cpy, versionPropName),
+		Extra:   extra(cpy),
+	}, nil
+}
+
+func parseContributors(i interface{}) ([]Contributor, error) {
+	var ret []Contributor
+	for _, v := range list(i) {
+		c, err := parseContributor(v)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, nil
+}
+
+func parseContributor(v interface{}) (Contributor, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		s, _ := v.(string)
+		return Contributor{Title: s, raw: v}, nil
+	}
+	cpy, err := clone.Descriptor(m)
+	if err != nil {
+		return Contributor{}, err
+	}
+	return Contributor{
+		Title:        popString(cpy, titlePropName),
+		GivenName:    popString(cpy, "givenName"),
+		FamilyName:   popString(cpy, "familyName"),
+		Path:         popString(cpy, pathProp),
+		Email:        popString(cpy, "email"),
+		Organization: popString(cpy, "organization"),
+		Role:         popString(cpy, "role"),
+		Roles:        popStrings(cpy, "roles"),
+		Extra:        extra(cpy),
+	}, nil
+}
+
+func licensesToList(licenses []License) []interface{} {
+	var ret []interface{}
+	for _, l := range licenses {
+		if parsed, _ := parseLicense(l.raw); l.raw != nil && reflect.DeepEqual(parsed, l) {
+			ret = append(ret, l.raw)
+			continue
+		}
+		ret = append(ret, withExtra(l.Extra, map[string]interface{}{
+			nameProp:      l.Name,
+			pathProp:      l.Path,
+			titlePropName: l.Title,
+		}))
+	}
+	return ret
+}
+
+func sourcesToList(sources []Source) []interface{} {
+	var ret []interface{}
+	for _, s := range sources {
+		if parsed, _ := parseSource(s.raw); s.raw != nil && reflect.DeepEqual(parsed, s) {
+			ret = append(ret, s.raw)
+			continue
+		}
+		ret = append(ret, withExtra(s.Extra, map[string]interface{}{
+			titlePropName:   s.Title,
+			pathProp:        s.Path,
+			"email":         s.Email,
+			versionPropName: s.Version,
+		}))
+	}
+	return ret
+}
+
+func contributorsToList(contributors []Contributor) []interface{} {
+	var ret []interface{}
+	for _, c := range contributors {
+		if parsed, _ := parseContributor(c.raw); c.raw != nil && reflect.DeepEqual(parsed, c) {
+			ret = append(ret, c.raw)
+			continue
+		}
+		ret = append(ret, withExtra(c.Extra, map[string]interface{}{
+			titlePropName:  c.Title,
+			"givenName":    c.GivenName,
+			"familyName":   c.FamilyName,
+			pathProp:       c.Path,
+			"email":        c.Email,
+			"organization": c.Organization,
+			"role":         c.Role,
+			"roles":        stringsToList(c.Roles),
+		}))
+	}
+	return ret
+}
+
+// withExtra returns a new object containing the extra properties and the non-empty
+// passed-in properties. Empty properties do not remove the extra ones, which hold the
+// values the typed fields could not represent (e.g. a numeric version).
+func withExtra(extra map[string]interface{}, props map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(extra)+len(props))
+	for k, v := range extra {
+		ret[k] = v
+	}
+	for k, v := range props {
+		if !isEmptyProp(v) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func formatTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+func stringsToList(s []string) []interface{} {
+	var ret []interface{}
+	for _, v := range s {
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func stringProp(d map[string]interface{}, key string) string {
+	s, _ := d[key].(string)
+	return s
+}
+
+func stringsProp(d map[string]interface{}, key string) []string {
+	var ret []string
+	switch l := d[key].(type) {
+	case []string:
+		ret = append(ret, l...)
+	case []interface{}:
+		for _, v := range l {
+			if s, ok := v.(string); ok {
+				ret = append(ret, s)
+			}
+		}
+	}
+	return ret
+}
+
+func list(i interface{}) []interface{} {
+	l, _ := i.([]interface{})
+	return l
+}
+
+// popString returns the string property and removes it from the object.
+func popString(d map[string]interface{}, key string) string {
+	s, ok := d[key].(string)
+	if ok {
+		delete(d, key)
+	}
+	return s
+}
+
+// popStrings returns the list of strings property and removes it from the object. Lists
+// holding other values are kept in the object.
+func popStrings(d map[string]interface{}, key string) []string {
+	s := stringsProp(d, key)
+	if s == nil {
+		return nil
+	}
+	if l, ok := d[key].([]interface{}); ok && len(l) != len(s) {
+		return nil
+	}
+	delete(d, key)
+	return s
+}
+
+func extra(d map[string]interface{}) map[string]interface{} {
+	if len(d) == 0 {
+		return nil
+	}
+	return d
+}
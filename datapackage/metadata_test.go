@@ -0,0 +1,150 @@
+package datapackage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestPackage_Metadata(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		in := `{
+			"name": "pkg",
+			"title": "Package",
+			"version": "1.0.0",
+			"created": "2021-11-25T10:11:24Z",
+			"keywords": ["a", "b"],
+			"licenses": [{"name": "ODC-PDDL-1.0", "path": "http://opendatacommons.org/licenses/pddl/", "foo": "bar"}],
+			"contributors": [{"title": "Joe", "role": "author", "email": "joe@example.com"}],
+			"sources": [{"title": "World Bank", "path": "http://data.worldbank.org"}],
+			"resources": [{"name": "res1", "path": "foo.csv"}]
+		}`
+		pkg, err := FromString(in, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		m, err := pkg.Metadata()
+		is.NoErr(err)
+		is.Equal(m.Name, "pkg")
+		is.Equal(m.Title, "Package")
+		is.Equal(m.Version, "1.0.0")
+		is.Equal(m.Created, time.Date(2021, 11, 25, 10, 11, 24, 0, time.UTC))
+		is.Equal(m.Keywords, []string{"a", "b"})
+		is.Equal(m.Licenses, []License{{Name: "ODC-PDDL-1.0", Path: "http://opendatacommons.org/licenses/pddl/", Extra: map[string]interface{}{"foo": "bar"}}})
+		is.Equal(m.Contributors, []Contributor{{Title: "Joe", Role: "author", Email: "joe@example.com"}})
+		is.Equal(m.Sources, []Source{{Title: "World Bank", Path: "http://data.worldbank.org"}})
+	})
+	t.Run("InvalidCreated", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		pkg.descriptor["created"] = "yesterday"
+		_, err = pkg.Metadata()
+		is.True(err != nil)
+	})
+}
+
+func TestPackage_SetMetadata(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{
+			"title":     "Package",
+			"foo":       "bar",
+			"licenses":  []interface{}{map[string]interface{}{"name": "MIT", "foo": "bar"}},
+			"resources": []interface{}{r1},
+		}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		m, err := pkg.Metadata()
+		is.NoErr(err)
+		m.Title = ""
+		m.Name = "pkg"
+		m.Created = time.Date(2021, 11, 25, 10, 11, 24, 0, time.UTC)
+		is.NoErr(pkg.SetMetadata(m))
+		is.Equal(pkg.Descriptor(), map[string]interface{}{
+			"name":      "pkg",
+			"foo":       "bar",
+			"created":   "2021-11-25T10:11:24Z",
+			"licenses":  []interface{}{map[string]interface{}{"name": "MIT", "foo": "bar"}},
+			"profile":   "data-package",
+			"resources": []interface{}{r1Filled},
+		})
+	})
+	t.Run("RoundTripUnrepresentable", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{
+			"sources":      []interface{}{map[string]interface{}{"title": "World Bank", "version": 1.0}},
+			"contributors": []interface{}{map[string]interface{}{"title": "Joe", "roles": []interface{}{"author", 1.0}}},
+			"resources":    []interface{}{r1},
+		}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		m, err := pkg.Metadata()
+		is.NoErr(err)
+		m.Title = "Package"
+		is.NoErr(pkg.SetMetadata(m))
+		is.Equal(pkg.Descriptor(), map[string]interface{}{
+			"title":        "Package",
+			"sources":      []interface{}{map[string]interface{}{"title": "World Bank", "version": 1.0}},
+			"contributors": []interface{}{map[string]interface{}{"title": "Joe", "roles": []interface{}{"author", 1.0}}},
+			"profile":      "data-package",
+			"resources":    []interface{}{r1Filled},
+		})
+	})
+	t.Run("RoundTripStrings", func(t *testing.T) {
+		is := is.New(t)
+		licenses, err := parseLicenses([]interface{}{"MIT", map[string]interface{}{"name": "ODC-BY-1.0"}})
+		is.NoErr(err)
+		is.Equal(licenses[0].Name, "MIT")
+		is.Equal(licensesToList(licenses), []interface{}{"MIT", map[string]interface{}{"name": "ODC-BY-1.0"}})
+		licenses[0].Name = "ODC-PDDL-1.0"
+		is.Equal(licensesToList(licenses)[0], map[string]interface{}{"name": "ODC-PDDL-1.0"})
+
+		sources, err := parseSources([]interface{}{"World Bank"})
+		is.NoErr(err)
+		is.Equal(sources[0].Title, "World Bank")
+		is.Equal(sourcesToList(sources), []interface{}{"World Bank"})
+
+		contributors, err := parseContributors([]interface{}{"Joe"})
+		is.NoErr(err)
+		is.Equal(contributors[0].Title, "Joe")
+		is.Equal(contributorsToList(contributors), []interface{}{"Joe"})
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.True(pkg.SetName("Invalid Name") != nil)
+		is.True(pkg.SetLicenses(License{Name: "Invalid Name"}) != nil)
+		is.Equal(pkg.Descriptor(), map[string]interface{}{"profile": "data-package", "resources": []interface{}{r1Filled}})
+	})
+	t.Run("Setters", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.NoErr(pkg.SetName("pkg"))
+		is.NoErr(pkg.SetTitle("Package"))
+		is.NoErr(pkg.SetDescription("Description"))
+		is.NoErr(pkg.SetVersion("1.0.0"))
+		is.NoErr(pkg.SetKeywords("a", "b"))
+		is.NoErr(pkg.SetContributors(Contributor{Title: "Joe", Role: "author"}))
+		is.NoErr(pkg.SetSources(Source{Title: "World Bank"}))
+		is.NoErr(pkg.SetLicenses(License{Name: "MIT"}))
+		is.NoErr(pkg.SetCreated(time.Date(2021, 11, 25, 10, 11, 24, 0, time.UTC)))
+		m, err := pkg.Metadata()
+		is.NoErr(err)
+		is.Equal(m, Metadata{
+			Name:         "pkg",
+			Title:        "Package",
+			Description:  "Description",
+			Version:      "1.0.0",
+			Created:      time.Date(2021, 11, 25, 10, 11, 24, 0, time.UTC),
+			Keywords:     []string{"a", "b"},
+			Licenses:     []License{{Name: "MIT"}},
+			Contributors: []Contributor{{Title: "Joe", Role: "author"}},
+			Sources:      []Source{{Title: "World Bank"}},
+		})
+		is.NoErr(pkg.SetTitle(""))
+		_, ok := pkg.Descriptor()["title"]
+		is.True(!ok)
+	})
+}
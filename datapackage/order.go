@@ -0,0 +1,333 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SaveOption configures how descriptors are saved.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	canonical   bool
+	inline      bool
+	dataToFiles bool
+	inlineFiles int64 // Maximum size of the files to inline, zero if files are not inlined.
+	mode        os.FileMode
+}
+
+// CanonicalOrder writes descriptor properties in the canonical order (name, title, ...,
+// resources last), instead of keeping the order of the loaded descriptor.
+func CanonicalOrder() SaveOption {
+	return func(o *saveOptions) {
+		o.canonical = true
+	}
+}
+
+func newSaveOptions(opts []SaveOption) saveOptions {
+	var o saveOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Properties in canonical order. Remaining properties follow them alphabetically, resources
+// always come last.
+var canonicalProps = []string{
+	schemaURLProp, profilePropName, nameProp, idPropName, typeProp, titlePropName, descriptionPropName,
+	homepagePropName, versionPropName, createdPropName, keywordsPropName, imagePropName,
+	pathProp, dataProp, formatProp, mediaTypeProp, encodingProp, bytesProp, hashProp,
+	licensesPropName, contributorsPropName, sourcesPropName, dialectProp, schemaProp,
+	fieldsProp, primaryKeyProp, foreignKeysProp, missingValuesProp,
+}
+
+var canonicalRank = func() map[string]int {
+	m := make(map[string]int, len(canonicalProps)+1)
+	for i, p := range canonicalProps {
+		m[p] = i
+	}
+	m[resourcePropName] = len(canonicalProps) + 1
+	return m
+}()
+
+const defaultIndent = "  "
+
+// descriptorLayout holds how a loaded descriptor was laid out, so it can be saved the same way.
+type descriptorLayout struct {
+	order           *keyOrder
+	indent          string
+	trailingNewline bool
+}
+
+// jsonLayout returns the layout of the passed-in JSON descriptor.
+func jsonLayout(buf []byte) *descriptorLayout {
+	l := &descriptorLayout{}
+	l.order, _, _ = jsonKeyOrder(json.NewDecoder(bytes.NewReader(buf)))
+	trimmed := bytes.TrimRight(buf, " \t\r\n")
+	l.trailingNewline = len(trimmed) < len(buf)
+	l.indent = jsonIndent(trimmed)
+	return l
+}
+
+// jsonIndent returns the indentation unit of the JSON document, based on its first indented
+// line. It returns an empty string for compact documents and the default indentation
+// if it can not be figured out.
+func jsonIndent(buf []byte) string {
+	depth, inString, escaped := 0, false, false
+	for i := 0; i < len(buf); i++ {
+		c := buf[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		case c == '\n':
+			line := buf[i+1:]
+			content := bytes.TrimLeft(line, " \t")
+			ws := line[:len(line)-len(content)]
+			if len(content) == 0 || content[0] == '\n' || content[0] == '\r' {
+				continue
+			}
+			d := depth
+			if content[0] == '}' || content[0] == ']' {
+				d--
+			}
+			if d <= 0 || len(ws)%d != 0 {
+				return defaultIndent
+			}
+			unit := ws[:len(ws)/d]
+			if len(unit) == 0 || !bytes.Equal(bytes.Repeat(unit, d), ws) {
+				return defaultIndent
+			}
+			return string(unit)
+		}
+	}
+	return ""
+}
+
+// keyOrder holds the original order of the properties of a descriptor object, or the
+// order of the properties of the items of a descriptor array.
+type keyOrder struct {
+	keys  []string
+	props map[string]*keyOrder
+	items []*keyOrder
+	// name is the value of the name property, used to match items after the array changed
+	// (e.g. a resource was removed).
+	name string
+}
+
+func (o *keyOrder) prop(key string) *keyOrder {
+	if o == nil {
+		return nil
+	}
+	return o.props[key]
+}
+
+func (o *keyOrder) item(i int, v interface{}) *keyOrder {
+	if o == nil {
+		return nil
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		if name, ok := m[nameProp].(string); ok {
+			for _, it := range o.items {
+				if it != nil && it.name == name {
+					return it
+				}
+			}
+			return nil
+		}
+	}
+	if i < len(o.items) {
+		return o.items[i]
+	}
+	return nil
+}
+
+// sortedKeys returns the object keys in the order they must be written. Keys with
+// known order come first, followed by the new ones in alphabetical order (or canonical order).
+func (o *keyOrder) sortedKeys(m map[string]interface{}, canonical bool) []string {
+	var keys []string
+	seen := make(map[string]struct{}, len(m))
+	if o != nil && !canonical {
+		for _, k := range o.keys {
+			if _, ok := m[k]; ok {
+				keys = append(keys, k)
+				seen[k] = struct{}{}
+			}
+		}
+	}
+	var rest []string
+	for k := range m {
+		if _, ok := seen[k]; !ok {
+			rest = append(rest, k)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if canonical {
+			ri, iok := canonicalRank[rest[i]]
+			rj, jok := canonicalRank[rest[j]]
+			if !iok {
+				ri = len(canonicalProps)
+			}
+			if !jok {
+				rj = len(canonicalProps)
+			}
+			if ri != rj {
+				return ri < rj
+			}
+		}
+		return rest[i] < rest[j]
+	})
+	// Resources are kept last.
+	if n := len(keys); n > 0 && keys[n-1] == resourcePropName {
+		return append(append(keys[:n-1:n-1], rest...), resourcePropName)
+	}
+	return append(keys, rest...)
+}
+
+// jsonKeyOrder reads the next JSON value, returning its key order (nil for scalars)
+// and the scalar value.
+func jsonKeyOrder(dec *json.Decoder) (*keyOrder, json.Token, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return nil, t, nil
+	}
+	o := &keyOrder{}
+	switch delim {
+	case '{':
+		o.props = map[string]*keyOrder{}
+		for dec.More() {
+			kt, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key, _ := kt.(string)
+			child, v, err := jsonKeyOrder(dec)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, ok := o.props[key]; !ok {
+				o.keys = append(o.keys, key)
+			}
+			o.props[key] = child
+			if s, ok := v.(string); ok && key == nameProp {
+				o.name = s
+			}
+		}
+	case '[':
+		for dec.More() {
+			child, _, err := jsonKeyOrder(dec)
+			if err != nil {
+				return nil, nil, err
+			}
+			o.items = append(o.items, child)
+		}
+	}
+	// Closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	return o, nil, nil
+}
+
+// yamlKeyOrder returns the key order of the YAML value.
+func yamlKeyOrder(n *yaml.Node) *keyOrder {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil
+		}
+		return yamlKeyOrder(n.Content[0])
+	case yaml.AliasNode:
+		return yamlKeyOrder(n.Alias)
+	case yaml.MappingNode:
+		o := &keyOrder{props: map[string]*keyOrder{}}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i].Value, n.Content[i+1]
+			if _, ok := o.props[k]; !ok {
+				o.keys = append(o.keys, k)
+			}
+			o.props[k] = yamlKeyOrder(v)
+			if k == nameProp && v.Kind == yaml.ScalarNode {
+				o.name = v.Value
+			}
+		}
+		return o
+	case yaml.SequenceNode:
+		o := &keyOrder{}
+		for _, c := range n.Content {
+			o.items = append(o.items, yamlKeyOrder(c))
+		}
+		return o
+	}
+	return nil
+}
+
+// orderedObject is a descriptor object which is marshalled to JSON in the order of its keys.
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// ordered returns a representation of the descriptor value which is marshalled to JSON
+// in the passed-in order.
+func ordered(v interface{}, o *keyOrder, canonical bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			values[k] = ordered(val, o.prop(k), canonical)
+		}
+		return orderedObject{keys: o.sortedKeys(v, canonical), values: values}
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, val := range v {
+			l[i] = ordered(val, o.item(i, val), canonical)
+		}
+		return l
+	}
+	return v
+}
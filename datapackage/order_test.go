@@ -0,0 +1,117 @@
+package datapackage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestPackage_SaveDescriptor_Order(t *testing.T) {
+	const original = `{
+    "name": "pkg",
+    "resources": [
+        {
+            "path": "a.csv",
+            "name": "a"
+        },
+        {
+            "path": "b.csv",
+            "name": "b",
+            "encoding": "utf-8",
+            "profile": "data-resource"
+        }
+    ],
+    "profile": "data-package"
+}
+`
+	dir, err := ioutil.TempDir("", "datapackage_order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	save := func(pkg *Package, opts ...SaveOption) string {
+		fName := filepath.Join(dir, "datapackage.json")
+		if err := pkg.SaveDescriptor(fName, opts...); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := ioutil.ReadFile(fName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf)
+	}
+	t.Run("RoundTrip", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(original, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		// Default values filled in when loading are not saved, the ones of the source are kept.
+		is.Equal(save(pkg), original)
+
+		// Same for descriptors returned by Descriptor, which hold the default values.
+		is.NoErr(pkg.Update(pkg.Descriptor(), validator.InMemoryLoader()))
+		is.Equal(save(pkg), original)
+	})
+	t.Run("Modified", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(original, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		pkg.RemoveResource("a")
+		is.NoErr(pkg.SetTitle("Package"))
+		is.NoErr(pkg.AddResource(map[string]interface{}{"name": "c", "path": "c.csv"}))
+		is.Equal(save(pkg), `{
+    "name": "pkg",
+    "resources": [
+        {
+            "path": "b.csv",
+            "name": "b",
+            "encoding": "utf-8",
+            "profile": "data-resource"
+        },
+        {
+            "name": "c",
+            "path": "c.csv"
+        }
+    ],
+    "profile": "data-package",
+    "title": "Package"
+}
+`)
+	})
+	t.Run("Canonical", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"resources": [{"path": "a.csv", "name": "a", "foo": "bar"}], "title": "Package", "name": "pkg"}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(save(pkg, CanonicalOrder()), `{"name":"pkg","title":"Package","resources":[{"name":"a","path":"a.csv","foo":"bar"}]}`)
+	})
+	t.Run("Dialect", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"resources": [{"name": "a", "path": "a.csv", "dialect": {"delimiter": ";"}}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.GetResource("a").Descriptor()["dialect"], map[string]interface{}{"delimiter": ";", "doubleQuote": true})
+		is.Equal(save(pkg), `{"resources":[{"name":"a","path":"a.csv","dialect":{"delimiter":";"}}]}`)
+	})
+	t.Run("YAML", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromYAMLString("resources:\n  - path: a.csv\n    name: a\nname: pkg\n", ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		fName := filepath.Join(dir, "datapackage.yaml")
+		is.NoErr(pkg.SaveDescriptor(fName))
+		buf, err := ioutil.ReadFile(fName)
+		is.NoErr(err)
+		is.Equal(string(buf), "resources:\n  - path: a.csv\n    name: a\nname: pkg\n")
+	})
+}
+
+func TestJSONIndent(t *testing.T) {
+	is := is.New(t)
+	is.Equal(jsonIndent([]byte(`{"a": 1}`)), "")
+	is.Equal(jsonIndent([]byte("{\n\t\"a\": 1\n}")), "\t")
+	is.Equal(jsonIndent([]byte("{\"a\": [\n      1\n]}")), "   ")
+	is.Equal(jsonIndent([]byte("{\"a\": \"{\\\"\\n\",\n  \"b\": 1}")), "  ")
+	// Inconsistent indentation.
+	is.Equal(jsonIndent([]byte("{\"a\": [{\n\t\t\t\t\t\"b\": 1}]}")), defaultIndent)
+}
@@ -2,9 +2,7 @@ package datapackage
 
 import (
 	"archive/zip"
-	"bufio"
 	"bytes"
-	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +12,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/frictionlessdata/datapackage-go/clone"
 	"github.com/frictionlessdata/datapackage-go/validator"
@@ -28,27 +27,45 @@ const (
 	defaultResourceProfile        = "data-resource"
 	tabularDataPackageProfileName = "tabular-data-package"
 	descriptorFileNameWithinZip   = "datapackage.json"
+	defaultFileMode               = 0644
 )
 
-// Needed to registry gob types.
-// See FromReader for more details.
-func init() {
-	var i json.Number
-	gob.Register(i)
-}
-
 // Package represents a https://specs.frictionlessdata.io/data-package/
+//
+// Packages are safe for concurrent use by multiple goroutines. Resources are not: a resource
+// returned by the package must not be changed (e.g. Resource.SetTitle) concurrently with other
+// uses of the same resource. Each call to GetResource returns a distinct resource.
 type Package struct {
+	// mu guards the fields below. Descriptors and resource slices are never changed in place once
+	// set (they are replaced instead), so they can be used after the lock is released.
+	mu sync.RWMutex
+
 	resources []*Resource
 
 	basePath    string
 	descriptor  map[string]interface{}
 	valRegistry validator.Registry
+	layout      *descriptorLayout // Layout of the loaded descriptor, if any.
+	refs        map[string]string // External schema references, keyed by property.
+	filled      filledDefaults    // Default values filled in by New, which are not saved.
 }
 
 // GetResource return the resource which the passed-in name or nil if the resource is not part of the package.
+// The returned resource is a copy: changes made through its setters are applied to the package,
+// without changing the resources in use by other goroutines.
 func (p *Package) GetResource(name string) *Resource {
-	for _, r := range p.resources {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	r := findResource(p.resources, name)
+	if r == nil {
+		return nil
+	}
+	cpy := *r
+	return &cpy
+}
+
+func findResource(resources []*Resource, name string) *Resource {
+	for _, r := range resources {
 		if r.name == name {
 			return r
 		}
@@ -56,8 +73,17 @@ func (p *Package) GetResource(name string) *Resource {
 	return nil
 }
 
+// snapshot returns the package descriptor and resources, which can be used without holding the lock.
+func (p *Package) snapshot() (map[string]interface{}, []*Resource) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.descriptor, p.resources
+}
+
 // ResourceNames return a slice containing the name of the resources.
 func (p *Package) ResourceNames() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	s := make([]string, len(p.resources))
 	for i, r := range p.resources {
 		s[i] = r.name
@@ -65,38 +91,62 @@ func (p *Package) ResourceNames() []string {
 	return s
 }
 
-// Resources returns a copy of data package resources.
+// Resources returns a copy of data package resources. As done by GetResource, changes made through
+// the setters of the returned resources are applied to the package.
 func (p *Package) Resources() []*Resource {
-	// NOTE: Ignoring errors because we are not changing anything. Just cloning a valid package descriptor and building
-	// its resources.
-	cpy, _ := clone.Descriptor(p.descriptor)
-	res, _ := buildResources(cpy[resourcePropName], p.basePath, p.valRegistry)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	res := make([]*Resource, len(p.resources))
+	for i, r := range p.resources {
+		cpy := *r
+		res[i] = &cpy
+	}
 	return res
 }
 
+// EachResource calls f for each package resource, in order, stopping at the first error, which is
+// returned. Resources are copies, as returned by GetResource, and f is free to change the package:
+// iteration happens over the resources the package had when EachResource was called.
+func (p *Package) EachResource(f func(*Resource) error) error {
+	_, resources := p.snapshot()
+	for _, r := range resources {
+		cpy := *r
+		if err := f(&cpy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AddResource adds a new resource to the package, updating its descriptor accordingly.
+// Only the new resource is validated.
 func (p *Package) AddResource(d map[string]interface{}) error {
-	resDesc, err := clone.Descriptor(d)
-	if err != nil {
-		return err
-	}
-	fillResourceDescriptorWithDefaultValues(resDesc)
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	rSlice, ok := p.descriptor[resourcePropName].([]interface{})
 	if !ok {
 		return fmt.Errorf("invalid resources property:\"%v\"", p.descriptor[resourcePropName])
 	}
-	rSlice = append(rSlice, resDesc)
-	r, err := buildResources(rSlice, p.basePath, p.valRegistry)
+	r, err := newResource(d, p.basePath, specVersion(p.descriptor), p.valRegistry, false)
+	if err != nil {
+		return err
+	}
+	// External references are loaded by the resource.
+	resDesc, err := clone.Descriptor(r.descriptor)
 	if err != nil {
 		return err
 	}
-	p.descriptor[resourcePropName] = rSlice
-	p.resources = r
+	r.pkg = p
+	n := len(rSlice)
+	p.descriptor = withProp(p.descriptor, resourcePropName, append(rSlice[:n:n], resDesc))
+	p.resources = append(p.resources[:len(p.resources):len(p.resources)], r)
 	return nil
 }
 
 //RemoveResource removes the resource from the package, updating its descriptor accordingly.
 func (p *Package) RemoveResource(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	index := -1
 	rSlice, ok := p.descriptor[resourcePropName].([]interface{})
 	if !ok {
@@ -110,18 +160,52 @@ func (p *Package) RemoveResource(name string) {
 		}
 	}
 	if index > -1 {
-		newSlice := append(rSlice[:index], rSlice[index+1:]...)
-		r, err := buildResources(newSlice, p.basePath, p.valRegistry)
-		if err != nil {
-			return
-		}
-		p.descriptor[resourcePropName] = newSlice
-		p.resources = r
+		// Resources are kept in the same order as their descriptors.
+		p.descriptor = withProp(p.descriptor, resourcePropName, append(rSlice[:index:index], rSlice[index+1:]...))
+		p.resources = append(p.resources[:index:index], p.resources[index+1:]...)
+	}
+}
+
+// setResources sets the package resources, linking them to the package. Changes made through
+// resource setters are then reflected in the package descriptor.
+func (p *Package) setResources(resources []*Resource) {
+	for _, r := range resources {
+		r.pkg = p
 	}
+	p.resources = resources
+}
+
+// withProp returns a shallow copy of the descriptor, setting the property.
+func withProp(d map[string]interface{}, prop string, value interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(d)+1)
+	for k, v := range d {
+		ret[k] = v
+	}
+	ret[prop] = value
+	return ret
+}
+
+// shallowCopy returns a shallow copy of the descriptor.
+func shallowCopy(d map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		ret[k] = v
+	}
+	return ret
+}
+
+// SpecVersion returns the version of the Data Package specification the package targets (SpecV1 or SpecV2).
+// Packages declaring a v2 (or custom) $schema target v2, all others target v1.
+func (p *Package) SpecVersion() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return specVersion(p.descriptor)
 }
 
 // Descriptor returns a deep copy of the underlying descriptor which describes the package.
 func (p *Package) Descriptor() map[string]interface{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	// Package cescriptor is always valid. Don't need to make the interface overcomplicated.
 	c, _ := clone.Descriptor(p.descriptor)
 	return c
@@ -130,19 +214,55 @@ func (p *Package) Descriptor() map[string]interface{} {
 // Update the package with the passed-in descriptor. The package will only be updated if the
 // the new descriptor is valid, otherwise the error will be returned.
 func (p *Package) Update(newDescriptor map[string]interface{}, loaders ...validator.RegistryLoader) error {
-	newP, err := New(newDescriptor, p.basePath, loaders...)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.updateWith(newDescriptor, loaders...)
+}
+
+// updateWith replaces the package with a new one created from the passed-in descriptor.
+// p.mu must be held.
+func (p *Package) updateWith(d map[string]interface{}, loaders ...validator.RegistryLoader) error {
+	newP, err := New(d, p.basePath, loaders...)
 	if err != nil {
 		return err
 	}
-	*p = *newP
+	p.replace(newP)
 	return nil
 }
 
-func (p *Package) write(w io.Writer) error {
-	b, err := json.MarshalIndent(p.descriptor, "", "  ")
+// replace replaces the package by the passed-in one, keeping the descriptor layout.
+// p.mu must be held.
+func (p *Package) replace(newP *Package) {
+	keepRefs(newP.resources, p.resources)
+	keepResourceDefaults(newP.resources, p.resources)
+	p.refs = unchangedRefs(newP.refs, p.refs, p.descriptor, newP.descriptor)
+	p.filled = keepDefaults(newP.filled, p.filled, newP.descriptor)
+	p.basePath = newP.basePath
+	p.descriptor = newP.descriptor
+	p.valRegistry = newP.valRegistry
+	p.setResources(newP.resources)
+}
+
+func (p *Package) write(w io.Writer, opts saveOptions) error {
+	indent, newline := defaultIndent, false
+	var order *keyOrder
+	if p.layout != nil {
+		indent, newline, order = p.layout.indent, p.layout.trailingNewline, p.layout.order
+	}
+	d := ordered(p.savedDescriptor(opts), order, opts.canonical)
+	var b []byte
+	var err error
+	if indent == "" {
+		b, err = json.Marshal(d)
+	} else {
+		b, err = json.MarshalIndent(d, "", indent)
+	}
 	if err != nil {
 		return err
 	}
+	if newline {
+		b = append(b, '\n')
+	}
 	_, err = w.Write(b)
 	if err != nil {
 		return err
@@ -150,16 +270,99 @@ func (p *Package) write(w io.Writer) error {
 	return nil
 }
 
-// SaveDescriptor saves the data package descriptor to the passed-in file path.
-// It create creates the named file with mode 0666 (before umask), truncating
-// it if it already exists.
-func (p *Package) SaveDescriptor(path string) error {
-	f, err := os.Create(path)
+// SaveDescriptor saves the data package descriptor to the passed-in file path. Paths with the
+// ".yaml" or ".yml" extension are saved as YAML, all others as JSON.
+//
+// The descriptor is written to a temporary file in the same directory, which is synced and then
+// renamed, so a crash or encoding failure never leaves a partially written descriptor. Existing
+// files keep their mode, new ones are created with mode 0644. See FileMode.
+//
+// Descriptors which were loaded keep their property order and indentation. Properties
+// added afterwards, as well as the ones of descriptors created in code, are written in
+// alphabetical order. See CanonicalOrder. Default values filled in by New (e.g. profile and
+// encoding) are only saved if they were changed.
+//
+// Schemas and dialects loaded from external references are saved as the original references,
+// unless they were changed. See InlineReferences.
+func (p *Package) SaveDescriptor(path string, opts ...SaveOption) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.saveDescriptor(path, opts...)
+}
+
+// FileMode sets the mode of the saved descriptor file.
+func FileMode(mode os.FileMode) SaveOption {
+	return func(o *saveOptions) {
+		o.mode = mode
+	}
+}
+
+func (p *Package) saveDescriptor(path string, opts ...SaveOption) error {
+	o := newSaveOptions(opts)
+	var buf bytes.Buffer
+	var err error
+	if isYAML(path) {
+		err = p.writeYAML(&buf, o)
+	} else {
+		err = p.write(&buf, o)
+	}
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	return p.write(f)
+	_, err = writeFileAtomic(path, &buf, descriptorFileMode(path, o))
+	return err
+}
+
+// descriptorFileMode returns the mode the descriptor file is saved with.
+func descriptorFileMode(path string, o saveOptions) os.FileMode {
+	if o.mode != 0 {
+		return o.mode
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return defaultFileMode
+}
+
+// WriteDescriptor writes the JSON data package descriptor to the passed-in writer, the same way
+// SaveDescriptor saves it.
+func (p *Package) WriteDescriptor(w io.Writer, opts ...SaveOption) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.write(w, newSaveOptions(opts))
+}
+
+// writeFileAtomic writes the contents to the file, replacing it atomically. The contents are
+// written to a temporary file in the same directory, which is synced before being renamed.
+func writeFileAtomic(path string, r io.Reader, mode os.FileMode) (int64, error) {
+	dir := filepath.Dir(path)
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return 0, err
+	}
+	// Syncing the directory makes the rename durable. Not all platforms support it.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return n, nil
 }
 
 // Zip saves a zip-compressed file containing the package descriptor and all resource data.
@@ -174,12 +377,17 @@ func (p *Package) Zip(path string) error {
 
 	// Saving descriptor.
 	descriptorPath := filepath.Join(dir, descriptorFileNameWithinZip)
-	if err := p.SaveDescriptor(descriptorPath); err != nil {
+	p.mu.RLock()
+	resources := p.resources
+	// External schemas and dialects are not part of the bundle.
+	err = p.saveDescriptor(descriptorPath, InlineReferences())
+	p.mu.RUnlock()
+	if err != nil {
 		return err
 	}
 	// Downloading resources.
 	fPaths := []string{descriptorPath}
-	for _, r := range p.resources {
+	for _, r := range resources {
 		for _, p := range r.path {
 			_, c, err := read(filepath.Join(r.basePath, p))
 			if err != nil {
@@ -247,45 +455,78 @@ func New(descriptor map[string]interface{}, basePath string, loaders ...validato
 	if err != nil {
 		return nil, err
 	}
-	fillPackageDescriptorWithDefaultValues(cpy)
-	loadPackageSchemas(cpy)
-	profile, ok := cpy[profilePropName].(string)
+	version := specVersion(cpy)
+	refs, resRefs, err := loadPackageReferences(cpy, basePath)
+	if err != nil {
+		return nil, err
+	}
+	filled, resFilled := fillPackageDescriptorWithDefaultValues(cpy, version)
+	baseProfile := defaultDataPackageProfile
+	if version == SpecV2 {
+		baseProfile = dataPackageProfileV2
+	}
+	profile, ok := descriptorProfile(cpy, version, baseProfile)
 	if !ok {
-		return nil, fmt.Errorf("%s property MUST be a string", profilePropName)
+		return nil, fmt.Errorf("%s property MUST be a string", profileProperty(version))
 	}
 	registry, err := validator.NewRegistry(loaders...)
 	if err != nil {
 		return nil, err
 	}
-	if err := validator.Validate(cpy, profile, registry); err != nil {
+	if err := validateProfile(cpy, profile, baseProfile, basePath, registry); err != nil {
 		return nil, err
 	}
-	resources, err := buildResources(cpy[resourcePropName], basePath, registry)
+	resources, err := buildResources(cpy[resourcePropName], basePath, version, registry)
 	if err != nil {
 		return nil, err
 	}
-	return &Package{
-		resources:   resources,
+	for i, r := range resources {
+		r.refs = resRefs[i]
+		r.filled = resFilled[i]
+	}
+	pkg := &Package{
 		descriptor:  cpy,
 		valRegistry: registry,
 		basePath:    basePath,
-	}, nil
+		refs:        refs,
+		filled:      filled,
+	}
+	pkg.setResources(resources)
+	return pkg, nil
 }
 
 // FromReader creates a data package from an io.Reader.
 func FromReader(r io.Reader, basePath string, loaders ...validator.RegistryLoader) (*Package, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, err := decodeDescriptor(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := New(descriptor, basePath, loaders...)
+	if err != nil {
+		return nil, err
+	}
+	pkg.layout = jsonLayout(buf)
+	return pkg, nil
+}
+
+// decodeDescriptor decodes a JSON descriptor.
+func decodeDescriptor(r io.Reader) (map[string]interface{}, error) {
 	// JSON doesn't differentiate between floats and integers. When parsed from JSON, large integers
 	// get converted into scientific notation
 	// Issue: https://github.com/frictionlessdata/datapackage-go/issues/28
 	// Example at TestBigNumBytesIsValid.
-	d := json.NewDecoder(bufio.NewReader(r))
+	d := json.NewDecoder(r)
 	d.UseNumber()
 
 	var descriptor map[string]interface{}
 	if err := d.Decode(&descriptor); err != nil {
 		return nil, err
 	}
-	return New(descriptor, basePath, loaders...)
+	return descriptor, nil
 }
 
 // FromString creates a data package from a string representation of the package descriptor.
@@ -295,11 +536,15 @@ func FromString(in string, basePath string, loaders ...validator.RegistryLoader)
 
 // Load the data package descriptor from the specified URL or file path.
 // If path has the ".zip" extension, it will be saved in local filesystem and decompressed before loading.
+// Descriptors with the ".yaml" or ".yml" extension are parsed as YAML, all others as JSON.
 func Load(path string, loaders ...validator.RegistryLoader) (*Package, error) {
 	localPath, contents, err := read(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading path contents (%s): %w", path, err)
 	}
+	if isYAML(path) {
+		return FromYAMLReader(bytes.NewBuffer(contents), getBasepath(path), loaders...)
+	}
 	if !strings.HasSuffix(path, ".zip") {
 		return FromReader(bytes.NewBuffer(contents), getBasepath(path), loaders...)
 	}
@@ -312,10 +557,12 @@ func Load(path string, loaders ...validator.RegistryLoader) (*Package, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error unzipping path contents (%s): %w", localPath, err)
 	}
-	if _, ok := fNames[descriptorFileNameWithinZip]; ok {
-		return Load(filepath.Join(dir, descriptorFileNameWithinZip), loaders...)
+	for _, name := range []string{descriptorFileNameWithinZip, yamlDescriptorFileNameWithinZip} {
+		if _, ok := fNames[name]; ok {
+			return Load(filepath.Join(dir, name), loaders...)
+		}
 	}
-	return nil, fmt.Errorf("zip file %s does not contain a file called %s", localPath, descriptorFileNameWithinZip)
+	return nil, fmt.Errorf("zip file %s does not contain a file called %s or %s", localPath, descriptorFileNameWithinZip, yamlDescriptorFileNameWithinZip)
 }
 
 func read(path string) (string, []byte, error) {
@@ -386,43 +633,109 @@ func unzip(archive, basePath string) (map[string]struct{}, error) {
 	return fileNames, nil
 }
 
-func fillPackageDescriptorWithDefaultValues(descriptor map[string]interface{}) {
-	if descriptor[profilePropName] == nil {
-		descriptor[profilePropName] = defaultDataPackageProfile
+// fillPackageDescriptorWithDefaultValues fills the package and resource descriptors with default
+// values, returning the values filled in the package and in each resource.
+func fillPackageDescriptorWithDefaultValues(descriptor map[string]interface{}, version string) (filledDefaults, []filledDefaults) {
+	var filled filledDefaults
+	// The v2 specification does not rely on default profiles.
+	if version == SpecV1 {
+		filled = filled.fill(descriptor, "", profilePropName, defaultDataPackageProfile)
+	}
+	rSlice, _ := descriptor[resourcePropName].([]interface{})
+	resFilled := make([]filledDefaults, len(rSlice))
+	for i := range rSlice {
+		r, ok := rSlice[i].(map[string]interface{})
+		if ok {
+			resFilled[i] = fillResourceDescriptorWithDefaultValues(r, resourceSpecVersion(r, version))
+		}
 	}
-	rSlice, ok := descriptor[resourcePropName].([]interface{})
-	if ok {
-		for i := range rSlice {
-			r, ok := rSlice[i].(map[string]interface{})
-			if ok {
-				fillResourceDescriptorWithDefaultValues(r)
-			}
+	return filled, resFilled
+}
+
+// filledDefaults holds the default values filled in a descriptor, keyed by the property path
+// (e.g. "dialect/delimiter"). Filled values are left out when saving the descriptor, as long
+// as they are not changed, so saved descriptors only hold the properties of their source.
+type filledDefaults map[string]interface{}
+
+// fill sets the property of the descriptor object found at prefix to the default value, if
+// the property is not set. It returns the filled defaults, which might be a new map.
+func (f filledDefaults) fill(d map[string]interface{}, prefix, prop string, value interface{}) filledDefaults {
+	if d[prop] != nil {
+		return f
+	}
+	d[prop] = value
+	if f == nil {
+		f = make(filledDefaults)
+	}
+	f[prefix+prop] = value
+	return f
+}
+
+// lookupPath returns the value of the descriptor property at the passed-in path.
+func lookupPath(d map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, "/")
+	for _, part := range parts[:len(parts)-1] {
+		var ok bool
+		if d, ok = d[part].(map[string]interface{}); !ok {
+			return nil, false
 		}
 	}
+	v, ok := d[parts[len(parts)-1]]
+	return v, ok
 }
 
-func loadPackageSchemas(d map[string]interface{}) error {
-	var err error
-	if schStr, ok := d[schemaProp].(string); ok {
-		d[schemaProp], err = loadSchema(schStr)
-		if err != nil {
-			return err
+// withoutDefaults returns a shallow copy of the descriptor without the filled default values
+// which were not changed. Nested objects holding filled values are copied too.
+func withoutDefaults(d map[string]interface{}, filled filledDefaults) map[string]interface{} {
+	ret := shallowCopy(d)
+	for path, value := range filled {
+		if v, ok := lookupPath(ret, path); !ok || !reflect.DeepEqual(v, value) {
+			continue
 		}
+		m := ret
+		parts := strings.Split(path, "/")
+		for _, part := range parts[:len(parts)-1] {
+			nested := shallowCopy(m[part].(map[string]interface{}))
+			m[part] = nested
+			m = nested
+		}
+		delete(m, parts[len(parts)-1])
 	}
-	resources, _ := d[resourcePropName].([]interface{})
-	for _, r := range resources {
-		resMap, _ := r.(map[string]interface{})
-		if schStr, ok := resMap[schemaProp].(string); ok {
-			resMap[schemaProp], err = loadSchema(schStr)
-			if err != nil {
-				return err
+	return ret
+}
+
+// keepDefaults adds to the filled defaults the old ones whose values are still the same in
+// the descriptor. It keeps the defaults out of saved descriptors after the package is updated
+// with a descriptor returned by Descriptor, which holds them.
+func keepDefaults(filled, old filledDefaults, d map[string]interface{}) filledDefaults {
+	for path, value := range old {
+		if _, ok := filled[path]; ok {
+			continue
+		}
+		if v, ok := lookupPath(d, path); ok && reflect.DeepEqual(v, value) {
+			if filled == nil {
+				filled = make(filledDefaults)
 			}
+			filled[path] = value
+		}
+	}
+	return filled
+}
+
+// keepResourceDefaults keeps the filled defaults of the old resources, matched by name. See keepDefaults.
+func keepResourceDefaults(resources, old []*Resource) {
+	byName := make(map[string]*Resource, len(old))
+	for _, r := range old {
+		byName[r.name] = r
+	}
+	for _, r := range resources {
+		if o, ok := byName[r.name]; ok {
+			r.filled = keepDefaults(r.filled, o.filled, r.descriptor)
 		}
 	}
-	return nil
 }
 
-func buildResources(resI interface{}, basePath string, reg validator.Registry) ([]*Resource, error) {
+func buildResources(resI interface{}, basePath, version string, reg validator.Registry) ([]*Resource, error) {
 	rSlice, ok := resI.([]interface{})
 	if !ok {
 		return nil, fmt.Errorf("invalid resources property. Value:\"%v\" Type:\"%v\"", resI, reflect.TypeOf(resI))
@@ -433,11 +746,10 @@ func buildResources(resI interface{}, basePath string, reg validator.Registry) (
 		if !ok {
 			return nil, fmt.Errorf("resources must be a json object. got:%v", rInt)
 		}
-		r, err := NewResource(rDesc, reg)
+		r, err := newResource(rDesc, basePath, version, reg, false)
 		if err != nil {
 			return nil, err
 		}
-		r.basePath = basePath
 		resources[pos] = r
 	}
 	return resources, nil
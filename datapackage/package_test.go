@@ -14,7 +14,9 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 
 	"github.com/frictionlessdata/datapackage-go/validator"
 	"github.com/matryer/is"
@@ -34,6 +36,16 @@ var r1Str = `{
     }
   ]
 }`
+
+// r1SavedStr is the saved descriptor of a package holding r1. Filled default values are not saved.
+var r1SavedStr = `{
+  "resources": [
+    {
+      "name": "res1",
+      "path": "foo.csv"
+    }
+  ]
+}`
 var r2 = map[string]interface{}{"name": "res2", "path": "bar.csv"}
 var r2Filled = map[string]interface{}{"name": "res2", "path": "bar.csv", "profile": "data-resource", "encoding": "utf-8"}
 
@@ -158,6 +170,17 @@ func TestPackage_AddResource(t *testing.T) {
 		is.Equal(resDesc[0], r1Filled)
 		is.Equal(resDesc[1], r2Filled)
 	})
+	t.Run("KeepsResources", func(t *testing.T) {
+		is := is.New(t)
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		res1 := pkg.resources[0]
+		is.NoErr(pkg.AddResource(r2))
+		is.True(pkg.resources[0] == res1)
+		is.True(pkg.resources[1].pkg == pkg)
+		pkg.RemoveResource("res1")
+		is.Equal(pkg.ResourceNames(), []string{"res2"})
+		is.Equal(pkg.descriptor["resources"], []interface{}{r2Filled})
+	})
 	t.Run("InvalidResource", func(t *testing.T) {
 		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
 		if err := pkg.AddResource(invalidResource); err == nil {
@@ -189,6 +212,89 @@ func TestPackage_RemoveResource(t *testing.T) {
 		is.Equal(len(pkg.resources), 1)
 		is.Equal(pkg.resources[0].name, "res1")
 	})
+	t.Run("KeepsPreviousDescriptor", func(t *testing.T) {
+		is := is.New(t)
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1, r2}}, ".", validator.InMemoryLoader())
+		d, resources := pkg.snapshot()
+		pkg.RemoveResource("res1")
+
+		// Readers might still be using the previous descriptor and resources.
+		is.Equal(d["resources"], []interface{}{r1Filled, r2Filled})
+		is.Equal(len(resources), 2)
+	})
+}
+
+func TestPackage_Concurrency(t *testing.T) {
+	is := is.New(t)
+	pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+	other, err := New(map[string]interface{}{"resources": []interface{}{r2}}, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+	dir, err := ioutil.TempDir("", "datapackage_concurrency")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	run := func(f func(i int) error) {
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := f(i); err != nil {
+					errs <- err
+				}
+			}(i)
+		}
+	}
+	// Writers.
+	run(func(i int) error {
+		name := fmt.Sprintf("res%d", i+10)
+		if err := pkg.AddResource(map[string]interface{}{"name": name, "path": "foo.csv"}); err != nil {
+			return err
+		}
+		pkg.RemoveResource(name)
+		return nil
+	})
+	run(func(i int) error { return pkg.SetTitle(fmt.Sprintf("Package %d", i)) })
+	run(func(i int) error { return pkg.Update(pkg.Descriptor()) })
+	run(func(i int) error {
+		if r := pkg.GetResource("res1"); r != nil {
+			return r.SetTitle(fmt.Sprintf("Resource %d", i))
+		}
+		return nil
+	})
+	run(func(i int) error {
+		return pkg.ApplyPatch(Patch{{Op: PatchAdd, Path: "/keywords", Value: []interface{}{strconv.Itoa(i)}}})
+	})
+	// Readers.
+	run(func(i int) error {
+		pkg.Descriptor()
+		pkg.ResourceNames()
+		pkg.Resources()
+		pkg.SpecVersion()
+		_, err := pkg.Metadata()
+		return err
+	})
+	run(func(i int) error {
+		if _, err := json.Marshal(pkg); err != nil {
+			return err
+		}
+		return pkg.SaveDescriptor(filepath.Join(dir, fmt.Sprintf("datapackage%d.json", i)))
+	})
+	run(func(i int) error {
+		Diff(pkg, other)
+		other.CreatePatch(pkg)
+		return nil
+	})
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		is.NoErr(err)
+	}
+	// Updates made from a previous descriptor might bring back removed resources.
+	is.Equal(pkg.ResourceNames()[0], "res1")
+	is.True(strings.HasPrefix(pkg.GetResource("res1").Title(), "Resource"))
 }
 
 func TestPackage_ResourceNames(t *testing.T) {
@@ -209,6 +315,90 @@ func TestPackage_Resources(t *testing.T) {
 	resources = append(resources, &Resource{name: "foo"})
 	is.Equal(len(resources), 3)
 	is.Equal(len(pkg.ResourceNames()), 2)
+
+	// Changes made through setters are applied to the package.
+	is.NoErr(resources[0].SetTitle("Resource"))
+	is.Equal(pkg.GetResource("res1").Title(), "Resource")
+}
+
+func TestPackage_EachResource(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1, r2}}, ".", validator.InMemoryLoader())
+		var names []string
+		is.NoErr(pkg.EachResource(func(r *Resource) error {
+			names = append(names, r.name)
+			// Changing the package while iterating.
+			pkg.RemoveResource(r.name)
+			return nil
+		}))
+		is.Equal(names, []string{"res1", "res2"})
+		is.Equal(len(pkg.ResourceNames()), 0)
+	})
+	t.Run("Error", func(t *testing.T) {
+		is := is.New(t)
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1, r2}}, ".", validator.InMemoryLoader())
+		calls := 0
+		err := pkg.EachResource(func(r *Resource) error {
+			calls++
+			return fmt.Errorf("foo")
+		})
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(calls, 1)
+	})
+}
+
+// benchmarkPackage returns a package with the passed-in number of resources.
+func benchmarkPackage(b *testing.B, n int) *Package {
+	resources := make([]interface{}, n)
+	for i := range resources {
+		resources[i] = map[string]interface{}{"name": fmt.Sprintf("res%d", i), "path": "foo.csv"}
+	}
+	pkg, err := New(map[string]interface{}{"resources": resources}, ".", validator.InMemoryLoader())
+	if err != nil {
+		b.Fatal(err)
+	}
+	return pkg
+}
+
+func BenchmarkPackage_Resources(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		pkg := benchmarkPackage(b, n)
+		b.Run(fmt.Sprintf("Resources%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pkg.Resources()
+			}
+		})
+		b.Run(fmt.Sprintf("EachResource%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				pkg.EachResource(func(*Resource) error { return nil })
+			}
+		})
+		// Previous approach, rebuilding all resources.
+		b.Run(fmt.Sprintf("Rebuild%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := buildResources(pkg.descriptor[resourcePropName], pkg.basePath, SpecV1, pkg.valRegistry); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPackage_AddResource(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		pkg := benchmarkPackage(b, n)
+		b.Run(fmt.Sprintf("Resources%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := pkg.AddResource(map[string]interface{}{"name": "new", "path": "foo.csv"}); err != nil {
+					b.Fatal(err)
+				}
+				pkg.RemoveResource("new")
+			}
+		})
+	}
 }
 
 func TestPackage_Descriptor(t *testing.T) {
@@ -285,10 +475,75 @@ func TestPackage_SaveDescriptor(t *testing.T) {
 		// Checking descriptor contents.
 		buf, err := ioutil.ReadFile(fName)
 		is.NoErr(err)
+		is.Equal(string(buf), r1SavedStr)
+	})
+	t.Run("FileMode", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_save")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		fName := filepath.Join(dir, "pkg.json")
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		mode := func() os.FileMode {
+			info, err := os.Stat(fName)
+			is.NoErr(err)
+			return info.Mode().Perm()
+		}
+
+		is.NoErr(pkg.SaveDescriptor(fName))
+		is.Equal(mode(), os.FileMode(0644))
+		is.NoErr(pkg.SaveDescriptor(fName, FileMode(0600)))
+		is.Equal(mode(), os.FileMode(0600))
+		is.NoErr(pkg.SaveDescriptor(fName)) // Keeps the mode of the existing file.
+		is.Equal(mode(), os.FileMode(0600))
+
+		// No temporary files are left behind.
+		files, err := ioutil.ReadDir(dir)
+		is.NoErr(err)
+		is.Equal(len(files), 1)
+	})
+	t.Run("Atomic", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_save")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		fName := filepath.Join(dir, "pkg.json")
+		is.NoErr(ioutil.WriteFile(fName, []byte(r1Str), 0644))
+
+		// A failed write keeps the previous contents.
+		if _, err := writeFileAtomic(fName, iotest.ErrReader(fmt.Errorf("boom")), 0644); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		buf, err := ioutil.ReadFile(fName)
+		is.NoErr(err)
 		is.Equal(string(buf), r1Str)
+		files, err := ioutil.ReadDir(dir)
+		is.NoErr(err)
+		is.Equal(len(files), 1)
+
+		// Directories which do not exist are not created.
+		pkg, _ := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		if err := pkg.SaveDescriptor(filepath.Join(dir, "foo", "pkg.json")); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
 	})
 }
 
+func TestPackage_WriteDescriptor(t *testing.T) {
+	is := is.New(t)
+	pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+	var buf bytes.Buffer
+	is.NoErr(pkg.WriteDescriptor(&buf))
+	is.Equal(buf.String(), r1SavedStr)
+
+	// Options are applied as when saving descriptors.
+	is.NoErr(pkg.SetTitle("Package"))
+	buf.Reset()
+	is.NoErr(pkg.WriteDescriptor(&buf, CanonicalOrder()))
+	is.True(strings.Index(buf.String(), `"title"`) < strings.Index(buf.String(), `"resources"`))
+}
+
 func TestPackage_Zip(t *testing.T) {
 	is := is.New(t)
 
@@ -325,11 +580,10 @@ func TestPackage_Zip(t *testing.T) {
 	defer descriptor.Close()
 	io.Copy(&buf, descriptor)
 
-	filledDescriptor := `{
-  "profile": "data-package",
+	// Original property order is kept and default values filled when loading are not saved.
+	savedDescriptor := `{
   "resources": [
     {
-      "encoding": "utf-8",
       "name": "res1",
       "path": "data.csv",
       "profile": "tabular-data-resource",
@@ -344,7 +598,7 @@ func TestPackage_Zip(t *testing.T) {
     }
   ]
 }`
-	is.Equal(buf.String(), filledDescriptor)
+	is.Equal(buf.String(), savedDescriptor)
 
 	buf.Reset()
 	data, err := reader.File[1].Open()
@@ -646,3 +900,173 @@ func TestBigNumBytesIsValid(t *testing.T) {
 	is.NoErr(err)
 	is.Equal(pkg.GetResource("732920043605108807").Descriptor()["bytes"], json.Number("17747417"))
 }
+
+func TestCustomProfile(t *testing.T) {
+	// Custom profile requiring the package to have a title.
+	profile := `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"required": ["title"]
+	}`
+	t.Run("Remote", func(t *testing.T) {
+		is := is.New(t)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, profile)
+		}))
+		defer ts.Close()
+		_, err := New(map[string]interface{}{"profile": ts.URL, "title": "foo", "resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		_, err = New(map[string]interface{}{"profile": ts.URL, "resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.True(err != nil) // Missing title.
+	})
+	t.Run("RelativeToBasePath", func(t *testing.T) {
+		is := is.New(t)
+		dir, err := ioutil.TempDir("", "datapackage_profile")
+		is.NoErr(err)
+		defer os.RemoveAll(dir)
+		is.NoErr(ioutil.WriteFile(filepath.Join(dir, "profile.json"), []byte(profile), 0666))
+
+		_, err = New(map[string]interface{}{"profile": "profile.json", "title": "foo", "resources": []interface{}{r1}}, dir, validator.InMemoryLoader())
+		is.NoErr(err)
+		_, err = New(map[string]interface{}{"profile": "profile.json", "resources": []interface{}{r1}}, dir, validator.InMemoryLoader())
+		is.True(err != nil) // Missing title.
+	})
+	t.Run("InvalidAgainstBaseProfile", func(t *testing.T) {
+		is := is.New(t)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, profile)
+		}))
+		defer ts.Close()
+		// The custom profile is satisfied, but data-package requires resources.
+		_, err := New(map[string]interface{}{"profile": ts.URL, "title": "foo", "resources": []interface{}{}}, ".", validator.InMemoryLoader())
+		is.True(err != nil)
+	})
+	t.Run("InvalidPath", func(t *testing.T) {
+		is := is.New(t)
+		_, err := New(map[string]interface{}{"profile": "../profile.json", "resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.True(err != nil)
+	})
+}
+
+func TestSpecV2(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		in := `{
+			"$schema": "https://datapackage.org/profiles/2.0/datapackage.json",
+			"name": "pkg",
+			"resources": [{
+				"name": "res1",
+				"path": "data.csv",
+				"type": "table",
+				"dialect": {"header": true, "headerRows": [1], "commentChar": "#"},
+				"schema": {
+					"fieldsMatch": "subset",
+					"missingValues": [{"value": "", "label": "empty"}],
+					"fields": [
+						{"name": "name", "type": "string", "missingValues": ["-"]},
+						{"name": "level", "type": "integer", "categories": [1, 2, 3], "categoriesOrdered": true}
+					]
+				}
+			}]
+		}`
+		pkg, err := FromString(in, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.SpecVersion(), SpecV2)
+		_, hasProfile := pkg.Descriptor()["profile"]
+		is.True(!hasProfile) // v2 descriptors are not filled with v1 defaults.
+
+		res := pkg.GetResource("res1")
+		is.Equal(res.SpecVersion(), SpecV2)
+		is.True(res.Tabular())
+		_, hasProfile = res.Descriptor()["profile"]
+		is.True(!hasProfile)
+
+		sch, err := res.GetSchema()
+		is.NoErr(err)
+		is.Equal(sch.MissingValues, []string{""})
+		is.Equal(sch.Fields[0].MissingValues, map[string]struct{}{"-": {}})
+		is.Equal(sch.Fields[1].MissingValues, map[string]struct{}{"": {}})
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		data := []struct {
+			desc string
+			in   string
+		}{
+			{"NoResources", `{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "resources": []}`},
+			{"ResourceWithoutName", `{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "resources": [{"path": "data.csv"}]}`},
+			{"InvalidFieldsMatch", `{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "resources": [{"name": "res", "path": "data.csv", "schema": {"fieldsMatch": "foo", "fields": [{"name": "a"}]}}]}`},
+			{"InvalidResourceType", `{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "resources": [{"name": "res", "path": "data.csv", "type": "foo"}]}`},
+			{"SchemaNotAString", `{"$schema": 1, "resources": [{"name": "res", "path": "data.csv"}]}`},
+		}
+		for _, d := range data {
+			d := d
+			t.Run(d.desc, func(t *testing.T) {
+				if _, err := FromString(d.in, ".", validator.InMemoryLoader()); err == nil {
+					t.Fatalf("want:err got:nil")
+				}
+			})
+		}
+	})
+	t.Run("TableProperties", func(t *testing.T) {
+		newResource := func(t *testing.T, dialect, schema string) *Resource {
+			in := fmt.Sprintf(`{
+				"$schema": "https://datapackage.org/profiles/2.0/datapackage.json",
+				"resources": [{"name": "res", "data": "a\n1", "format": "csv", "dialect": %s, "schema": %s}]
+			}`, dialect, schema)
+			pkg, err := FromString(in, ".", validator.InMemoryLoader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return pkg.GetResource("res")
+		}
+		t.Run("Defaults", func(t *testing.T) {
+			is := is.New(t)
+			res := newResource(t, `{"headerRows": [1]}`, `{"fieldsMatch": "exact", "fields": [{"name": "a", "type": "integer"}]}`)
+			contents, err := res.ReadAll()
+			is.NoErr(err)
+			is.Equal(contents, [][]string{{"1"}})
+		})
+		data := []struct {
+			desc    string
+			dialect string
+			schema  string
+		}{
+			{"HeaderRows", `{"headerRows": [1, 2]}`, `{"fields": [{"name": "a"}]}`},
+			{"CommentChar", `{"commentChar": "#"}`, `{"fields": [{"name": "a"}]}`},
+			{"FieldsMatch", `{}`, `{"fieldsMatch": "subset", "fields": [{"name": "a"}]}`},
+			{"Categories", `{}`, `{"fields": [{"name": "a", "type": "integer", "categories": [1, 2]}]}`},
+		}
+		for _, d := range data {
+			d := d
+			t.Run(d.desc, func(t *testing.T) {
+				res := newResource(t, d.dialect, d.schema)
+				if _, err := res.ReadAll(); err == nil {
+					t.Fatalf("want:err got:nil")
+				}
+				var rows []struct{ A int64 }
+				if err := res.Cast(&rows); err == nil {
+					t.Fatalf("want:err got:nil")
+				}
+			})
+		}
+	})
+	t.Run("V1", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"resources": [{"name": "res1", "path": "data.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.SpecVersion(), SpecV1)
+		is.Equal(pkg.GetResource("res1").SpecVersion(), SpecV1)
+
+		pkg, err = FromString(`{"$schema": "https://datapackage.org/profiles/1.0/datapackage.json", "resources": [{"name": "res1", "path": "data.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.SpecVersion(), SpecV1)
+		is.Equal(pkg.Descriptor()["profile"], "data-package")
+	})
+	t.Run("AddResource", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "resources": [{"name": "res1", "path": "data.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.NoErr(pkg.AddResource(map[string]interface{}{"name": "Res2", "path": "data.csv"})) // v2 names are not restricted to lower case.
+		is.Equal(pkg.GetResource("Res2").SpecVersion(), SpecV2)
+	})
+}
@@ -0,0 +1,457 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+	"github.com/frictionlessdata/datapackage-go/validator"
+)
+
+// JSON Patch operations, as defined by RFC 6902.
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// PatchOperation is a JSON Patch (RFC 6902) operation. Paths are JSON Pointers (RFC 6901) into
+// the package descriptor.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// Patch is a JSON Patch (RFC 6902) document, which can be encoded and decoded using encoding/json.
+type Patch []PatchOperation
+
+func (op PatchOperation) hasValue() bool {
+	return op.Op == PatchAdd || op.Op == PatchReplace || op.Op == PatchTest
+}
+
+// MarshalJSON encodes the operation, implementing json.Marshaler.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	o := orderedObject{keys: []string{"op"}, values: map[string]interface{}{"op": op.Op, "path": op.Path}}
+	if op.Op == PatchMove || op.Op == PatchCopy {
+		o.keys = append(o.keys, "from")
+		o.values["from"] = op.From
+	}
+	o.keys = append(o.keys, "path")
+	if op.hasValue() {
+		o.keys = append(o.keys, "value")
+		o.values["value"] = op.Value
+	}
+	return json.Marshal(o)
+}
+
+// UnmarshalJSON decodes the operation, implementing json.Unmarshaler. Numbers are kept
+// intact, as done by FromReader.
+func (op *PatchOperation) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	var o PatchOperation
+	for k, dst := range map[string]*string{"op": &o.Op, "path": &o.Path, "from": &o.From} {
+		raw, ok := m[k]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, dst); err != nil {
+			return fmt.Errorf("invalid patch operation %s: %w", k, err)
+		}
+	}
+	if _, ok := m["op"]; !ok {
+		return fmt.Errorf("patch operation without op")
+	}
+	if _, ok := m["path"]; !ok {
+		return fmt.Errorf("patch operation without path")
+	}
+	if _, ok := m["from"]; !ok && (o.Op == PatchMove || o.Op == PatchCopy) {
+		return fmt.Errorf("%s operation without from", o.Op)
+	}
+	if o.hasValue() {
+		raw, ok := m["value"]
+		if !ok {
+			return fmt.Errorf("%s operation without value", o.Op)
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&o.Value); err != nil {
+			return fmt.Errorf("invalid patch operation value: %w", err)
+		}
+	}
+	*op = o
+	return nil
+}
+
+// CreatePatch returns the JSON Patch which transforms the descriptor of the package into the
+// descriptor of the passed-in package.
+func (p *Package) CreatePatch(to *Package) Patch {
+	from, _ := p.snapshot()
+	d, _ := to.snapshot()
+	return diffValues(from, d, "", nil)
+}
+
+// ApplyPatch applies the JSON Patch to the package descriptor. As done by Update, the package
+// is only changed if all operations succeed and the resulting descriptor is valid. Packages
+// keep their validator registry, unless loaders are passed in.
+func (p *Package) ApplyPatch(patch Patch, loaders ...validator.RegistryLoader) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cpy, err := clone.Descriptor(p.descriptor)
+	if err != nil {
+		return err
+	}
+	var doc interface{} = cpy
+	for i, op := range patch {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("patched descriptor must be an object, got:%T", doc)
+	}
+	if p.valRegistry == nil || len(loaders) > 0 {
+		return p.updateWith(d, loaders...)
+	}
+	return p.update(d)
+}
+
+// diffValues returns the operations which transform a into b.
+func diffValues(a, b interface{}, path string, patch Patch) Patch {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a)+len(b))
+		for k := range a {
+			keys = append(keys, k)
+		}
+		for k := range b {
+			if _, ok := a[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "/" + escapePointer(k)
+			va, inA := a[k]
+			vb, inB := b[k]
+			switch {
+			case !inA:
+				patch = append(patch, PatchOperation{Op: PatchAdd, Path: p, Value: cloneValue(vb)})
+			case !inB:
+				patch = append(patch, PatchOperation{Op: PatchRemove, Path: p})
+			default:
+				patch = diffValues(va, vb, p, patch)
+			}
+		}
+		return patch
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(a)
+		if len(b) < n {
+			n = len(b)
+		}
+		for i := 0; i < n; i++ {
+			patch = diffValues(a[i], b[i], path+"/"+strconv.Itoa(i), patch)
+		}
+		// Removing from the end, so indexes do not shift.
+		for i := len(a) - 1; i >= n; i-- {
+			patch = append(patch, PatchOperation{Op: PatchRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+		for _, v := range b[n:] {
+			patch = append(patch, PatchOperation{Op: PatchAdd, Path: path + "/-", Value: cloneValue(v)})
+		}
+		return patch
+	}
+	if !jsonEqual(a, b) {
+		patch = append(patch, PatchOperation{Op: PatchReplace, Path: path, Value: cloneValue(b)})
+	}
+	return patch
+}
+
+func applyOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case PatchAdd:
+		v, err := clone.Value(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, v)
+	case PatchRemove:
+		doc, _, err := removeValue(doc, tokens)
+		return doc, err
+	case PatchReplace:
+		if _, err := getValue(doc, tokens); err != nil {
+			return nil, err
+		}
+		v, err := clone.Value(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			return v, nil
+		}
+		doc, _, err := removeValue(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, v)
+	case PatchMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path == op.From {
+			_, err := getValue(doc, from)
+			return doc, err
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can not move a value into one of its children")
+		}
+		doc, v, err := removeValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, v)
+	case PatchCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, tokens, cloneValue(v))
+	case PatchTest:
+		v, err := getValue(doc, tokens)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(v, op.Value) {
+			return nil, fmt.Errorf("test failed, want:%v got:%v", op.Value, v)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("invalid operation:%q", op.Op)
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// parsePointer returns the reference tokens of the JSON Pointer.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer:%q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, t := range tokens {
+		tokens[i] = unescape.Replace(t)
+	}
+	return tokens, nil
+}
+
+// arrayIndex returns the array index referenced by the token. The end of the array ("-") is only
+// valid when adding values.
+func arrayIndex(token string, length int, adding bool) (int, error) {
+	if token == "-" && adding {
+		return length, nil
+	}
+	max := length - 1
+	if adding {
+		max = length
+	}
+	// Indexes are made of digits, without leading zeros.
+	i, err := strconv.Atoi(token)
+	if err != nil || strings.Trim(token, "0123456789") != "" || (len(token) > 1 && token[0] == '0') || i > max {
+		return 0, fmt.Errorf("invalid array index:%q", token)
+	}
+	return i, nil
+}
+
+func getValue(doc interface{}, tokens []string) (interface{}, error) {
+	for _, t := range tokens {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			child, ok := v[t]
+			if !ok {
+				return nil, fmt.Errorf("property %q not found", t)
+			}
+			doc = child
+		case []interface{}:
+			i, err := arrayIndex(t, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("can not get %q from a %T", t, doc)
+		}
+	}
+	return doc, nil
+}
+
+// setChild calls the function with the parent of the value referenced by the tokens, replacing the
+// parent by the returned value.
+func setChild(doc interface{}, tokens []string, f func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return f(doc, tokens[0])
+	}
+	child, err := getValue(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	newChild, err := setChild(child, tokens[1:], f)
+	if err != nil {
+		return nil, err
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		v[tokens[0]] = newChild
+	case []interface{}:
+		i, _ := arrayIndex(tokens[0], len(v), false)
+		v[i] = newChild
+	}
+	return doc, nil
+}
+
+func addValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return setChild(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			v[token] = value
+			return v, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(v), true)
+			if err != nil {
+				return nil, err
+			}
+			v = append(v, nil)
+			copy(v[i+1:], v[i:])
+			v[i] = value
+			return v, nil
+		}
+		return nil, fmt.Errorf("can not add %q to a %T", token, parent)
+	})
+}
+
+func removeValue(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("can not remove the whole descriptor")
+	}
+	var removed interface{}
+	doc, err := setChild(doc, tokens, func(parent interface{}, token string) (interface{}, error) {
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("property %q not found", token)
+			}
+			removed = child
+			delete(v, token)
+			return v, nil
+		case []interface{}:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = v[i]
+			return append(v[:i:i], v[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("can not remove %q from a %T", token, parent)
+	})
+	return doc, removed, err
+}
+
+// jsonEqual reports whether both JSON values are equal. Numbers are compared by their value,
+// regardless of their representation.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			bv, ok := b[k]
+			if !ok || !jsonEqual(v, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if fa, ok := jsonNumber(a); ok {
+		fb, ok := jsonNumber(b)
+		return ok && fa.Cmp(fb) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonNumber(v interface{}) (*big.Float, bool) {
+	var s string
+	switch v := v.(type) {
+	case json.Number:
+		s = v.String()
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	default:
+		return nil, false
+	}
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	return f, err == nil
+}
+
+// cloneValue deep-copies a descriptor value. Descriptors are always valid, so their values
+// can always be cloned.
+func cloneValue(v interface{}) interface{} {
+	c, _ := clone.Value(v)
+	return c
+}
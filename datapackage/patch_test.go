@@ -0,0 +1,104 @@
+package datapackage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestPackage_CreatePatch(t *testing.T) {
+	is := is.New(t)
+	a, err := FromString(`{"name": "pkg", "title": "Package", "keywords": ["a", "b", "c"], "resources": [{"name": "res1", "path": "foo.csv"}, {"name": "res2", "path": "bar.csv"}]}`, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+	b, err := FromString(`{"name": "pkg/1", "keywords": ["a"], "version": "1.0.0", "resources": [{"name": "res1", "path": "foo.csv", "bytes": 10}, {"name": "res2", "path": "bar.csv"}, {"name": "res3", "path": "baz.csv"}]}`, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+	patch := a.CreatePatch(b)
+	buf, err := json.Marshal(patch)
+	is.NoErr(err)
+	is.Equal(string(buf), `[{"op":"remove","path":"/keywords/2"},{"op":"remove","path":"/keywords/1"},{"op":"replace","path":"/name","value":"pkg/1"},{"op":"add","path":"/resources/0/bytes","value":10},{"op":"add","path":"/resources/-","value":{"encoding":"utf-8","name":"res3","path":"baz.csv","profile":"data-resource"}},{"op":"remove","path":"/title"},{"op":"add","path":"/version","value":"1.0.0"}]`)
+
+	var decoded Patch
+	is.NoErr(json.Unmarshal(buf, &decoded))
+	is.NoErr(a.ApplyPatch(decoded))
+	is.Equal(a.Descriptor(), b.Descriptor())
+	is.Equal(a.ResourceNames(), []string{"res1", "res2", "res3"})
+	is.Equal(len(a.CreatePatch(b)), 0)
+}
+
+func TestPackage_ApplyPatch(t *testing.T) {
+	const in = `{"name": "pkg", "a/b": {"m~n": 1}, "resources": [{"name": "res1", "path": "foo.csv"}, {"name": "res2", "path": "bar.csv"}]}`
+	t.Run("Operations", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(in, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		var patch Patch
+		is.NoErr(json.Unmarshal([]byte(`[
+			{"op": "test", "path": "/a~1b/m~0n", "value": 1.0},
+			{"op": "add", "path": "/resources/1", "value": {"name": "res3", "path": "baz.csv"}},
+			{"op": "move", "from": "/resources/0", "path": "/resources/-"},
+			{"op": "copy", "from": "/name", "path": "/title"},
+			{"op": "replace", "path": "/resources/0/path", "value": "qux.csv"},
+			{"op": "remove", "path": "/a~1b"},
+			{"op": "add", "path": "/extra", "value": null}
+		]`), &patch))
+		is.NoErr(pkg.ApplyPatch(patch))
+		is.Equal(pkg.ResourceNames(), []string{"res3", "res2", "res1"})
+		is.Equal(pkg.GetResource("res3").Descriptor()["path"], "qux.csv")
+		d := pkg.Descriptor()
+		is.Equal(d["title"], "pkg")
+		v, ok := d["extra"]
+		is.True(ok)
+		is.Equal(v, nil)
+		_, ok = d["a/b"]
+		is.True(!ok)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		data := []struct {
+			desc  string
+			patch string
+		}{
+			{"TestFailed", `[{"op": "test", "path": "/name", "value": "foo"}]`},
+			{"PathNotFound", `[{"op": "remove", "path": "/foo"}]`},
+			{"InvalidIndex", `[{"op": "add", "path": "/resources/01", "value": {}}]`},
+			{"IndexOutOfRange", `[{"op": "replace", "path": "/resources/2", "value": {}}]`},
+			{"InvalidPointer", `[{"op": "remove", "path": "name"}]`},
+			{"MoveIntoChild", `[{"op": "move", "from": "/resources", "path": "/resources/0"}]`},
+			{"UnknownOp", `[{"op": "foo", "path": "/name"}]`},
+			{"InvalidDescriptor", `[{"op": "remove", "path": "/resources"}]`},
+			{"NotAnObject", `[{"op": "replace", "path": "", "value": []}]`},
+			{"FailsAfterChanges", `[{"op": "replace", "path": "/name", "value": "foo"}, {"op": "remove", "path": "/bar"}]`},
+		}
+		for _, d := range data {
+			t.Run(d.desc, func(t *testing.T) {
+				is := is.New(t)
+				pkg, err := FromString(in, ".", validator.InMemoryLoader())
+				is.NoErr(err)
+				before := pkg.Descriptor()
+				var patch Patch
+				is.NoErr(json.Unmarshal([]byte(d.patch), &patch))
+				if err := pkg.ApplyPatch(patch); err == nil {
+					t.Fatalf("want:err got:nil")
+				}
+				is.Equal(pkg.Descriptor(), before)
+			})
+		}
+	})
+	t.Run("UnsupportedValue", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(in, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		if err := pkg.ApplyPatch(Patch{{Op: PatchAdd, Path: "/foo", Value: make(chan int)}}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("InvalidOperation", func(t *testing.T) {
+		for _, in := range []string{`[{"path": "/name"}]`, `[{"op": "add"}]`, `[{"op": "add", "path": "/name"}]`, `[{"op": "copy", "path": "/name"}]`} {
+			var patch Patch
+			if err := json.Unmarshal([]byte(in), &patch); err == nil {
+				t.Fatalf("want:err got:nil")
+			}
+		}
+	})
+}
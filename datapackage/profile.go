@@ -0,0 +1,62 @@
+package datapackage
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+)
+
+// isCustomProfile returns whether the profile references a JSONSchema by URL or file path
+// instead of using a registry identifier (e.g. "data-package").
+func isCustomProfile(profile string) bool {
+	return validator.IsThirdPartyProfile(profile) || strings.Contains(profile, "/") || strings.HasSuffix(profile, ".json")
+}
+
+// resolveProfile returns the URL of a custom profile. Relative paths are resolved
+// against the package base path and follow the same rules as resource paths.
+func resolveProfile(basePath, profile string) (string, error) {
+	if validator.IsThirdPartyProfile(profile) {
+		return profile, nil
+	}
+	if path.IsAbs(profile) || strings.HasPrefix(path.Clean(profile), "..") {
+		return "", fmt.Errorf("absolute paths (/) and relative parent paths (../) MUST NOT be used as profile:%s", profile)
+	}
+	p := joinPaths(basePath, profile)
+	if _, isRemote := parseRemotePath(p); isRemote {
+		return p, nil
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", fmt.Errorf("error resolving profile path (%s): %w", p, err)
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	if !strings.HasPrefix(u.Path, "/") { // Windows paths (C:/...).
+		u.Path = "/" + u.Path
+	}
+	return u.String(), nil
+}
+
+// validateProfile validates the descriptor against the passed-in profile. Custom profiles
+// are resolved relative to the basePath and the descriptor is also validated against the base
+// profile the custom one extends.
+func validateProfile(d map[string]interface{}, profile, baseProfile, basePath string, registry validator.Registry) error {
+	if !isCustomProfile(profile) {
+		return validator.Validate(d, profile, registry)
+	}
+	if err := validator.Validate(d, baseProfile, registry); err != nil {
+		return err
+	}
+	u, err := resolveProfile(basePath, profile)
+	if err != nil {
+		return err
+	}
+	v, err := validator.New(u)
+	if err != nil {
+		return fmt.Errorf("invalid Schema (Profile:%s): %q", profile, err)
+	}
+	return v.Validate(d)
+}
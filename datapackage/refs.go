@@ -0,0 +1,190 @@
+package datapackage
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Properties which might reference external descriptors.
+var refProps = []string{schemaProp, dialectProp}
+
+// InlineReferences writes external schemas and dialects inline, instead of keeping
+// their original references.
+func InlineReferences() SaveOption {
+	return func(o *saveOptions) {
+		o.inline = true
+	}
+}
+
+// loadExternal loads the descriptor referenced by the passed-in path or URL. YAML descriptors
+// are picked by the extension.
+func loadExternal(p string) (map[string]interface{}, error) {
+	var reader io.Reader
+	if strings.HasPrefix(p, "http") {
+		resp, err := http.Get(p)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		reader = resp.Body
+	} else {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+	buf, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFileDescriptor(p, buf)
+}
+
+func loadDialect(p string) (map[string]interface{}, error) {
+	return loadExternal(p)
+}
+
+// resolveRef resolves the external reference against the base path. References follow the
+// same rules as resource paths: they must either be fully qualified URLs or relative paths
+// which do not point to parent directories, whatever the base path. The references of standalone
+// resources (i.e. created by NewResource) are the exception: they are used as is, absolute paths included.
+func resolveRef(basePath, ref string, standalone bool) (string, error) {
+	if standalone {
+		return ref, nil
+	}
+	t, err := checkPath(ref)
+	if err != nil {
+		return "", err
+	}
+	if t == urlPath {
+		return ref, nil
+	}
+	return joinPaths(basePath, ref), nil
+}
+
+// loadReferences replaces the external schema and dialect references of the descriptor by
+// their contents, resolving them against the base path. It returns the references, keyed by property.
+// See resolveRef for the meaning of standalone.
+func loadReferences(d map[string]interface{}, basePath string, standalone bool) (map[string]string, error) {
+	var refs map[string]string
+	for _, prop := range refProps {
+		ref, ok := d[prop].(string)
+		if !ok {
+			continue
+		}
+		p, err := resolveRef(basePath, ref, standalone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s reference (%s): %w", prop, ref, err)
+		}
+		switch prop {
+		case schemaProp:
+			d[prop], err = loadSchema(p)
+		case dialectProp:
+			d[prop], err = loadDialect(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s (%s): %w", prop, ref, err)
+		}
+		if refs == nil {
+			refs = make(map[string]string)
+		}
+		refs[prop] = ref
+	}
+	return refs, nil
+}
+
+// loadPackageReferences loads the external references of the package and its resources. See loadReferences.
+func loadPackageReferences(d map[string]interface{}, basePath string) (map[string]string, []map[string]string, error) {
+	refs, err := loadReferences(d, basePath, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	resources, _ := d[resourcePropName].([]interface{})
+	resRefs := make([]map[string]string, len(resources))
+	for i, r := range resources {
+		if resMap, ok := r.(map[string]interface{}); ok {
+			if resRefs[i], err = loadReferences(resMap, basePath, false); err != nil {
+				return nil, nil, fmt.Errorf("resource %v: %w", resMap[nameProp], err)
+			}
+		}
+	}
+	return refs, resRefs, nil
+}
+
+// keepRefs keeps the external references of the old resources, matched by name, whose contents
+// have not changed.
+func keepRefs(resources, old []*Resource) {
+	byName := make(map[string]*Resource, len(old))
+	for _, r := range old {
+		byName[r.name] = r
+	}
+	for _, r := range resources {
+		if o, ok := byName[r.name]; ok {
+			r.refs = unchangedRefs(r.refs, o.refs, o.descriptor, r.descriptor)
+		}
+	}
+}
+
+// unchangedRefs adds to the references the old ones whose contents are the same in both descriptors.
+func unchangedRefs(refs, oldRefs map[string]string, old, d map[string]interface{}) map[string]string {
+	for prop, ref := range oldRefs {
+		if _, ok := refs[prop]; ok || !reflect.DeepEqual(old[prop], d[prop]) {
+			continue
+		}
+		if refs == nil {
+			refs = make(map[string]string)
+		}
+		refs[prop] = ref
+	}
+	return refs
+}
+
+// withRefs returns a shallow copy of the descriptor, replacing the contents loaded from external
+// references by the references themselves.
+func withRefs(d map[string]interface{}, refs map[string]string) map[string]interface{} {
+	ret := make(map[string]interface{}, len(d))
+	for k, v := range d {
+		ret[k] = v
+	}
+	for prop, ref := range refs {
+		if _, ok := ret[prop]; ok {
+			ret[prop] = ref
+		}
+	}
+	return ret
+}
+
+// savedDescriptor returns the package descriptor to be saved. Default values filled in by New
+// are left out, unless they were changed.
+func (p *Package) savedDescriptor(opts saveOptions) map[string]interface{} {
+	d := withoutDefaults(p.descriptor, p.filled)
+	if !opts.inline {
+		d = withRefs(d, p.refs)
+	}
+	rSlice, ok := d[resourcePropName].([]interface{})
+	if !ok || len(rSlice) != len(p.resources) {
+		return d
+	}
+	resources := make([]interface{}, len(rSlice))
+	for i, r := range rSlice {
+		resources[i] = r
+		rMap, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rMap = withoutDefaults(rMap, p.resources[i].filled)
+		if !opts.inline && len(p.resources[i].refs) > 0 {
+			rMap = withRefs(rMap, p.resources[i].refs)
+		}
+		resources[i] = rMap
+	}
+	d[resourcePropName] = resources
+	return d
+}
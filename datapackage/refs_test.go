@@ -0,0 +1,153 @@
+package datapackage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestExternalReferences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datapackage_refs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	const schPath, dialectPath = "schema.json", "dialect.yaml"
+	files := map[string]string{
+		schPath:      `{"fields": [{"name": "name", "type": "string"}, {"name": "age", "type": "integer"}]}`,
+		dialectPath:  "delimiter: ';'\nheader: true\n",
+		"data.csv":   "name;age\nfoo;42",
+		"other.json": `{"fields": [{"name": "name", "type": "string"}]}`,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	descriptor := fmt.Sprintf(`{"resources": [{"name": "res", "path": "data.csv", "profile": "tabular-data-resource", "schema": %q, "dialect": %q}]}`, schPath, dialectPath)
+	saved := func(pkg *Package, opts ...SaveOption) map[string]interface{} {
+		fName := filepath.Join(dir, "datapackage.json")
+		if err := pkg.SaveDescriptor(fName, opts...); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := ioutil.ReadFile(fName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d map[string]interface{}
+		if err := json.Unmarshal(buf, &d); err != nil {
+			t.Fatal(err)
+		}
+		return d["resources"].([]interface{})[0].(map[string]interface{})
+	}
+	t.Run("Load", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(descriptor, dir, validator.InMemoryLoader())
+		is.NoErr(err)
+		res := pkg.GetResource("res")
+		is.Equal(res.Descriptor()["dialect"].(map[string]interface{})["delimiter"], ";")
+		contents, err := res.ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"foo", "42"}})
+	})
+	t.Run("SaveReferences", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(descriptor, dir, validator.InMemoryLoader())
+		is.NoErr(err)
+		is.NoErr(pkg.SetTitle("Package"))
+		is.NoErr(pkg.AddResource(map[string]interface{}{"name": "other", "path": "data.csv"}))
+		r := saved(pkg)
+		is.Equal(r["schema"], schPath)
+		is.Equal(r["dialect"], dialectPath)
+
+		r = saved(pkg, InlineReferences())
+		is.Equal(r["dialect"].(map[string]interface{})["delimiter"], ";")
+		is.Equal(len(r["schema"].(map[string]interface{})["fields"].([]interface{})), 2)
+	})
+	t.Run("ChangedReference", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(descriptor, dir, validator.InMemoryLoader())
+		is.NoErr(err)
+		d := pkg.Descriptor()
+		res := d["resources"].([]interface{})[0].(map[string]interface{})
+		res["dialect"].(map[string]interface{})["delimiter"] = "|"
+		is.NoErr(pkg.Update(d, validator.InMemoryLoader()))
+		r := saved(pkg)
+		is.Equal(r["schema"], schPath)
+		is.Equal(r["dialect"].(map[string]interface{})["delimiter"], "|")
+
+		// Replacing the reference.
+		res["schema"] = "other.json"
+		is.NoErr(pkg.Update(d, validator.InMemoryLoader()))
+		is.Equal(saved(pkg)["schema"], "other.json")
+	})
+	t.Run("InvalidReference", func(t *testing.T) {
+		_, err := FromString(`{"resources": [{"name": "res", "path": "data.csv", "dialect": "foo.json"}]}`, dir, validator.InMemoryLoader())
+		if err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("UnsafeReference", func(t *testing.T) {
+		data := []struct {
+			desc string
+			ref  string
+		}{
+			{"Absolute", filepath.Join(dir, schPath)},
+			{"Parent", "../" + filepath.Base(dir) + "/" + schPath},
+			{"NotHTTP", "file://" + filepath.Join(dir, schPath)},
+		}
+		for _, d := range data {
+			t.Run(d.desc, func(t *testing.T) {
+				descriptor := fmt.Sprintf(`{"resources": [{"name": "res", "path": "data.csv", "schema": %q}]}`, d.ref)
+				// References are checked whatever the base path.
+				for _, basePath := range []string{dir, ""} {
+					if _, err := FromString(descriptor, basePath, validator.InMemoryLoader()); err == nil {
+						t.Fatalf("want:err got:nil")
+					}
+				}
+			})
+		}
+	})
+	t.Run("StandaloneResource", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResource(map[string]interface{}{"name": "res", "path": "data.csv", "schema": filepath.Join(dir, schPath)}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		sch, err := res.GetSchema()
+		is.NoErr(err)
+		is.Equal(len(sch.Fields), 2)
+	})
+}
+
+func TestExternalReferences_Remote(t *testing.T) {
+	is := is.New(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pkg/schemas/schema.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"fields": [{"name": "name", "type": "string"}]}`)
+	})
+	mux.HandleFunc("/pkg/dialect.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"delimiter": ";"}`)
+	})
+	mux.HandleFunc("/pkg/data.csv", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "name\nfoo")
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	pkg, err := FromString(`{"resources": [{"name": "res", "path": "data.csv", "profile": "tabular-data-resource", "schema": "schemas/schema.json", "dialect": "dialect.json"}]}`, ts.URL+"/pkg", validator.InMemoryLoader())
+	is.NoErr(err)
+	res := pkg.GetResource("res")
+	sch, err := res.GetSchema()
+	is.NoErr(err)
+	is.Equal(sch.Fields[0].Name, "name")
+	is.Equal(res.Descriptor()["dialect"].(map[string]interface{})["delimiter"], ";")
+	contents, err := res.ReadAll()
+	is.NoErr(err)
+	is.Equal(contents, [][]string{{"foo"}})
+}
@@ -2,7 +2,6 @@ package datapackage
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -10,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -56,9 +56,9 @@ const (
 	doubleQuoteProp      = "doubleQuote"
 )
 
-// dialect represents CSV dialect configuration options.
+// Dialect represents CSV dialect configuration options.
 // http://frictionlessdata.io/specs/csv-dialect/
-type dialect struct {
+type Dialect struct {
 	// Delimiter specifies the character sequence which should separate fields (aka columns).
 	Delimiter rune
 	// Specifies how to interpret whitespace which immediately follows a delimiter;
@@ -70,7 +70,7 @@ type dialect struct {
 	DoubleQuote bool
 }
 
-var defaultDialect = dialect{
+var defaultDialect = Dialect{
 	Delimiter:        ',',
 	SkipInitialSpace: true,
 	Header:           true,
@@ -84,6 +84,14 @@ type Resource struct {
 	data       interface{}
 	name       string
 	basePath   string
+	version    string
+	registry   validator.Registry
+	pkg        *Package          // Package the resource belongs to, if any.
+	refs       map[string]string // External schema and dialect references, keyed by property.
+	errPolicy  ErrorPolicy
+	policySet  bool // Whether errPolicy was set by WithErrorPolicy. See Cast.
+	errHandler func(*RowError)
+	filled     filledDefaults // Default values filled in by newResource, which are not saved.
 }
 
 // Name returns the resource name.
@@ -99,18 +107,26 @@ func (r *Resource) Descriptor() map[string]interface{} {
 }
 
 // Update the resource with the passed-in descriptor. The resource will only be updated if the
-// the new descriptor is valid, otherwise the error will be returned.
+// the new descriptor is valid, otherwise the error will be returned. As done by the setters,
+// resources which belong to a package also update the package descriptor.
 func (r *Resource) Update(d map[string]interface{}, loaders ...validator.RegistryLoader) error {
 	reg, err := validator.NewRegistry(loaders...)
 	if err != nil {
 		return err
 	}
-	res, err := NewResource(d, reg)
+	res, err := newResource(d, r.basePath, r.SpecVersion(), reg, r.pkg == nil)
 	if err != nil {
 		return err
 	}
-	*r = *res
-	return nil
+	return r.replace(res)
+}
+
+// SpecVersion returns the version of the Data Package specification the resource targets (SpecV1 or SpecV2).
+func (r *Resource) SpecVersion() string {
+	if r.version == "" {
+		return specVersion(r.descriptor)
+	}
+	return r.version
 }
 
 // Tabular checks whether the resource is tabular.
@@ -118,6 +134,9 @@ func (r *Resource) Tabular() bool {
 	if pStr, ok := r.descriptor[profileProp].(string); ok && pStr == tabularDataResourceProfile {
 		return true
 	}
+	if tStr, ok := r.descriptor[typeProp].(string); ok && tStr == tableResourceType {
+		return true
+	}
 	fStr, _ := r.descriptor[formatProp].(string)
 	if _, ok := tabularFormats[fStr]; ok {
 		return true
@@ -150,6 +169,28 @@ func dialectOpts(i interface{}) []csv.CreationOpts {
 	if i == nil {
 		return []csv.CreationOpts{}
 	}
+	d := parseDialect(i)
+	// Mapping dialect to proper csv CreationOpts.
+	opts := []csv.CreationOpts{csv.Delimiter(d.Delimiter)}
+	if !d.SkipInitialSpace {
+		opts = append(opts, csv.ConsiderInitialSpace())
+	}
+	if hasHeader(i) {
+		opts = append(opts, csv.LoadHeaders())
+	}
+	return opts
+}
+
+// hasHeader reports whether the files described by the dialect are read with a header row. For
+// compatibility, files of resources without dialect are read without header row: callers decide
+// by passing csv.LoadHeaders. Writers follow the dialect default instead, see NewWriter.
+func hasHeader(i interface{}) bool {
+	return i != nil && parseDialect(i).Header
+}
+
+// parseDialect returns the dialect described by the descriptor, using default values for the
+// missing or invalid properties.
+func parseDialect(i interface{}) Dialect {
 	d := defaultDialect
 	// Overriding default setting with valid values.
 	dMap, ok := i.(map[string]interface{})
@@ -166,23 +207,23 @@ func dialectOpts(i interface{}) []csv.CreationOpts {
 		if v, ok := dMap[headerProp].(bool); ok {
 			d.Header = v
 		}
+		if v, ok := dMap[doubleQuoteProp].(bool); ok {
+			d.DoubleQuote = v
+		}
 	}
-	// Mapping dialect to proper csv CreationOpts.
-	opts := []csv.CreationOpts{csv.Delimiter(d.Delimiter)}
-	if !d.SkipInitialSpace {
-		opts = append(opts, csv.ConsiderInitialSpace())
-	}
-	if d.Header {
-		opts = append(opts, csv.LoadHeaders())
-	}
-	return opts
+	return d
 }
 
-// GetTable returns a table object to access the data. Returns an error if the resource is not tabular.
+// GetTable returns a table object to access the data. Returns an error if the resource is not tabular
+// or if it uses v2 dialect or schema properties which are not supported yet (headerRows other than [1],
+// commentChar, commentRows, fieldsMatch other than "exact" and field categories).
 func (r *Resource) GetTable(opts ...csv.CreationOpts) (table.Table, error) {
 	if !r.Tabular() {
 		return nil, fmt.Errorf("methods iter/read are not supported for non tabular data")
 	}
+	if err := r.checkTableProperties(); err != nil {
+		return nil, err
+	}
 	fullOpts := append(dialectOpts(r.descriptor[dialectProp]), opts...)
 	// Inlined resources.
 	if r.data != nil {
@@ -196,6 +237,14 @@ func (r *Resource) GetTable(opts ...csv.CreationOpts) (table.Table, error) {
 	return csv.NewTable(func() (io.ReadCloser, error) { return loadContents(r.basePath, r.path, csvLoadFunc) }, fullOpts...)
 }
 
+// checkTableProperties returns an error if the resource data can not be accessed as described.
+func (r *Resource) checkTableProperties() error {
+	if p := unsupportedTableProperty(r.descriptor); p != "" {
+		return fmt.Errorf("resource %s: %s is not supported when reading or writing data", r.name, p)
+	}
+	return nil
+}
+
 func csvLoadFunc(p string) func() (io.ReadCloser, error) {
 	if strings.HasPrefix(p, "http") {
 		return csv.Remote(p)
@@ -309,35 +358,70 @@ func (r *Resource) GetSchema() (schema.Schema, error) {
 	if r.descriptor[schemaProp] == nil {
 		return schema.Schema{}, fmt.Errorf("schema is not declared in the descriptor")
 	}
-	buf, err := json.Marshal(r.descriptor[schemaProp])
-	if err != nil {
-		return schema.Schema{}, err
-	}
-	var s schema.Schema
-	if err := json.Unmarshal(buf, &s); err != nil {
-		return schema.Schema{}, fmt.Errorf("error unmarshaling schema:%w", err)
-	}
-	return s, nil
+	return parseSchema(r.descriptor[schemaProp])
 }
 
 // Cast resource contents.
 // The result argument must necessarily be the address for a slice. The slice
-// may be nil or previously allocated.
+// may be nil or previously allocated. Rows which can not be cast are handled
+// according to the resource error policy (see WithErrorPolicy). Unless a policy is set,
+// Cast keeps its original behaviour: all valid rows are cast and the invalid ones are
+// reported together as a *schema.ConversionError.
 func (r *Resource) Cast(out interface{}, opts ...csv.CreationOpts) error {
 	sch, err := r.GetSchema()
 	if err != nil {
 		return err
 	}
-	tbl, err := r.GetTable(opts...)
+	outv := reflect.ValueOf(out)
+	if outv.Kind() != reflect.Ptr || outv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out argument must be a slice address")
+	}
+	iter, err := r.Iter(opts...)
 	if err != nil {
 		return err
 	}
-	return sch.CastTable(tbl, out)
+	defer iter.Close()
+	h := r.rowErrorHandling()
+	if !r.policySet {
+		h.policy = CollectErrors
+	}
+	u := newUniqueChecker(&sch)
+	slicev := outv.Elem().Slice(0, 0) // Truncates the passed-in slice.
+	elemt := slicev.Type().Elem()
+	for index := 0; iter.Next(); index++ {
+		row := iter.Row()
+		elemp := reflect.New(elemt)
+		values, rowErr := castRow(&sch, index, row, elemp.Interface())
+		if rowErr == nil {
+			rowErr = u.check(index, row, values)
+		}
+		if rowErr != nil {
+			if err := h.handle(rowErr); err != nil {
+				outv.Elem().Set(slicev)
+				return err
+			}
+			continue
+		}
+		slicev = reflect.Append(slicev, elemp.Elem())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	outv.Elem().Set(slicev)
+	if !r.policySet && len(h.errs) > 0 {
+		convErr := &schema.ConversionError{}
+		for _, e := range h.errs {
+			convErr.Errors = append(convErr.Errors, schema.RowConversionError{LineNumber: e.Row, Err: e})
+		}
+		return convErr
+	}
+	return h.err()
 }
 
 // CastColumn casts a column from tabular resource contents.
 // The out argument must necessarily be the address for a slice. The slice
-// may be nil or previously allocated.
+// may be nil or previously allocated. Values which can not be cast are handled
+// according to the resource error policy (see WithErrorPolicy).
 func (r *Resource) CastColumn(name string, out interface{}, opts ...csv.CreationOpts) error {
 	sch, err := r.GetSchema()
 	if err != nil {
@@ -351,7 +435,33 @@ func (r *Resource) CastColumn(name string, out interface{}, opts ...csv.Creation
 	if err != nil {
 		return err
 	}
-	return sch.CastColumn(col, name, out)
+	f, pos := sch.GetField(name)
+	if pos == schema.InvalidPosition {
+		return fmt.Errorf("invalid field name \"%s\"", name)
+	}
+	outv := reflect.ValueOf(out)
+	if outv.Kind() != reflect.Ptr || outv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out argument must be a slice address")
+	}
+	h := r.rowErrorHandling()
+	slicev := outv.Elem().Slice(0, 0) // Truncates the passed-in slice.
+	elemt := slicev.Type().Elem()
+	for i, v := range col {
+		cast, err := f.Cast(v)
+		if err == nil && !reflect.TypeOf(cast).ConvertibleTo(elemt) {
+			err = fmt.Errorf("can not convert from %T to %v", cast, elemt)
+		}
+		if err != nil {
+			if err := h.handle(&RowError{Row: i, Field: name, Value: v, Err: err}); err != nil {
+				outv.Elem().Set(slicev)
+				return err
+			}
+			continue
+		}
+		slicev = reflect.Append(slicev, reflect.ValueOf(cast).Convert(elemt))
+	}
+	outv.Elem().Set(slicev)
+	return h.err()
 }
 
 // NewResourceWithDefaultRegistry creates a new Resource from the passed-in descriptor.
@@ -366,28 +476,44 @@ func NewResourceWithDefaultRegistry(d map[string]interface{}) (*Resource, error)
 
 // NewResource creates a new Resource from the passed-in descriptor, if valid. The
 // passed-in validator.Registry will be the source of profiles used in the validation.
+// Custom profiles referenced by relative paths are resolved against the current directory.
 func NewResource(d map[string]interface{}, registry validator.Registry) (*Resource, error) {
+	return newResource(d, "", SpecV1, registry, true)
+}
+
+// newResource creates a new Resource. Resources which do not declare the $schema property
+// target the passed-in specification version. Standalone resources do not belong to a package,
+// see resolveRef.
+func newResource(d map[string]interface{}, basePath, version string, registry validator.Registry, standalone bool) (*Resource, error) {
 	cpy, err := clone.Descriptor(d)
 	if err != nil {
 		return nil, err
 	}
-	if schStr, ok := cpy[schemaProp].(string); ok {
-		cpy[schemaProp], err = loadSchema(schStr)
-		if err != nil {
-			return nil, err
-		}
+	refs, err := loadReferences(cpy, basePath, standalone)
+	if err != nil {
+		return nil, err
 	}
-	fillResourceDescriptorWithDefaultValues(cpy)
-	profile, ok := cpy[profilePropName].(string)
+	version = resourceSpecVersion(cpy, version)
+	filled := fillResourceDescriptorWithDefaultValues(cpy, version)
+	baseProfile := defaultResourceProfile
+	if version == SpecV2 {
+		baseProfile = dataResourceProfileV2
+	}
+	profile, ok := descriptorProfile(cpy, version, baseProfile)
 	if !ok {
-		return nil, fmt.Errorf("profile property MUST be a string:\"%s\"", profilePropName)
+		return nil, fmt.Errorf("profile property MUST be a string:\"%s\"", profileProperty(version))
 	}
-	if err := validator.Validate(cpy, profile, registry); err != nil {
+	if err := validateProfile(cpy, profile, baseProfile, basePath, registry); err != nil {
 		return nil, err
 	}
 	r := Resource{
 		descriptor: cpy,
 		name:       cpy[nameProp].(string),
+		basePath:   basePath,
+		version:    version,
+		registry:   registry,
+		refs:       refs,
+		filled:     filled,
 	}
 	pathI := cpy[pathProp]
 	if pathI != nil {
@@ -407,25 +533,24 @@ func NewResource(d map[string]interface{}, registry validator.Registry) (*Resour
 	return &r, nil
 }
 
-func fillResourceDescriptorWithDefaultValues(r map[string]interface{}) {
-	if r[profilePropName] == nil {
-		r[profilePropName] = defaultResourceProfile
-	}
-	if r[encodingPropName] == nil {
-		r[encodingPropName] = defaultResourceEncoding
-	}
+// fillResourceDescriptorWithDefaultValues fills the resource descriptor with default values,
+// returning the filled ones.
+func fillResourceDescriptorWithDefaultValues(r map[string]interface{}, version string) filledDefaults {
+	// The v2 specification does not rely on default profiles and dialect properties
+	// are all optional.
+	if version == SpecV2 {
+		return nil
+	}
+	var filled filledDefaults
+	filled = filled.fill(r, "", profilePropName, defaultResourceProfile)
+	filled = filled.fill(r, "", encodingPropName, defaultResourceEncoding)
 	// Filling up mandatory values with default values if not set.
 	// That prevents users from the hassle of manually setting up all mandatory values.
-	if r[dialectProp] != nil {
-		if dMap, ok := r[dialectProp].(map[string]interface{}); ok {
-			if dMap[delimiterProp] == nil {
-				dMap[delimiterProp] = string(defaultDialect.Delimiter)
-			}
-			if dMap[doubleQuoteProp] == nil {
-				dMap[doubleQuoteProp] = defaultDialect.DoubleQuote
-			}
-		}
+	if dMap, ok := r[dialectProp].(map[string]interface{}); ok {
+		filled = filled.fill(dMap, dialectProp+"/", delimiterProp, string(defaultDialect.Delimiter))
+		filled = filled.fill(dMap, dialectProp+"/", doubleQuoteProp, defaultDialect.DoubleQuote)
 	}
+	return filled
 }
 
 func parseData(dataI interface{}, d map[string]interface{}) (interface{}, error) {
@@ -465,18 +590,9 @@ func parsePath(pathI interface{}, d map[string]interface{}) ([]string, error) {
 	var lastType, currType pathType
 	// Validation.
 	for index, p := range returned {
-		// Check if it is a relative path.
-		u, err := url.Parse(p)
-		if err != nil || u.Scheme == "" {
-			if path.IsAbs(p) || strings.HasPrefix(path.Clean(p), "..") {
-				return nil, fmt.Errorf("absolute paths (/) and relative parent paths (../) MUST NOT be used. Descriptor:%v", d)
-			}
-			currType = relativePath
-		} else { // Check if it is a valid URL.
-			if u.Scheme != "http" && u.Scheme != "https" {
-				return nil, fmt.Errorf("URLs MUST be fully qualified. MUST be using either http or https scheme. Descriptor:%v", d)
-			}
-			currType = urlPath
+		var err error
+		if currType, err = checkPath(p); err != nil {
+			return nil, fmt.Errorf("%v. Descriptor:%v", err, d)
 		}
 		if index > 0 {
 			if currType != lastType {
@@ -488,6 +604,24 @@ func parsePath(pathI interface{}, d map[string]interface{}) ([]string, error) {
 	return returned, nil
 }
 
+// checkPath checks whether the path is either a relative path which does not point to a
+// parent directory or a fully qualified URL, returning its type.
+func checkPath(p string) (pathType, error) {
+	// Check if it is a relative path.
+	u, err := url.Parse(p)
+	if err != nil || u.Scheme == "" {
+		if path.IsAbs(p) || strings.HasPrefix(path.Clean(p), "..") {
+			return relativePath, fmt.Errorf("absolute paths (/) and relative parent paths (../) MUST NOT be used")
+		}
+		return relativePath, nil
+	}
+	// Check if it is a valid URL.
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return urlPath, fmt.Errorf("URLs MUST be fully qualified. MUST be using either http or https scheme")
+	}
+	return urlPath, nil
+}
+
 // NewUncheckedResource returns an Resource instance based on the descriptor without any verification. The returned Resource might
 // not be valid.
 func NewUncheckedResource(d map[string]interface{}) *Resource {
@@ -509,8 +643,8 @@ func NewUncheckedResource(d map[string]interface{}) *Resource {
 // NewResourceFromString creates a new Resource from the passed-in JSON descriptor, if valid. The
 // passed-in validator.Registry will be the source of profiles used in the validation.
 func NewResourceFromString(res string, registry validator.Registry) (*Resource, error) {
-	var d map[string]interface{}
-	if err := json.Unmarshal([]byte(res), &d); err != nil {
+	d, err := decodeDescriptor(strings.NewReader(res))
+	if err != nil {
 		return nil, err
 	}
 	return NewResource(d, registry)
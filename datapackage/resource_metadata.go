@@ -0,0 +1,220 @@
+package datapackage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+)
+
+const (
+	encodingProp = "encoding"
+	bytesProp    = "bytes"
+	hashProp     = "hash"
+)
+
+// Title returns the resource title.
+func (r *Resource) Title() string {
+	return stringProp(r.descriptor, titlePropName)
+}
+
+// Description returns the resource description.
+func (r *Resource) Description() string {
+	return stringProp(r.descriptor, descriptionPropName)
+}
+
+// Format returns the resource format (e.g. "csv").
+func (r *Resource) Format() string {
+	return stringProp(r.descriptor, formatProp)
+}
+
+// MediaType returns the resource mediatype (e.g. "text/csv").
+func (r *Resource) MediaType() string {
+	return stringProp(r.descriptor, mediaTypeProp)
+}
+
+// Encoding returns the resource character encoding.
+func (r *Resource) Encoding() string {
+	return stringProp(r.descriptor, encodingProp)
+}
+
+// Bytes returns the size of the resource file in bytes. Zero is returned if the property
+// is not set.
+func (r *Resource) Bytes() int64 {
+	switch b := r.descriptor[bytesProp].(type) {
+	case json.Number:
+		i, _ := b.Int64()
+		return i
+	case float64:
+		return int64(b)
+	case int:
+		return int64(b)
+	case int64:
+		return b
+	}
+	return 0
+}
+
+// Hash returns the resource file hash (e.g. "sha256:...").
+func (r *Resource) Hash() string {
+	return stringProp(r.descriptor, hashProp)
+}
+
+// Path returns a copy of the resource path. Nil is returned for inline resources.
+func (r *Resource) Path() []string {
+	if r.path == nil {
+		return nil
+	}
+	return append([]string{}, r.path...)
+}
+
+// Licenses returns the resource licenses.
+func (r *Resource) Licenses() []License {
+	// Resource descriptor is always valid, licenses can be always parsed.
+	l, _ := parseLicenses(r.descriptor[licensesPropName])
+	return l
+}
+
+// Sources returns the resource sources.
+func (r *Resource) Sources() []Source {
+	// Resource descriptor is always valid, sources can be always parsed.
+	s, _ := parseSources(r.descriptor[sourcesPropName])
+	return s
+}
+
+// Set sets a single descriptor property. A nil value (or an empty string or list) removes
+// the property. The resource is only updated if the resulting descriptor is valid, otherwise
+// the error will be returned. Resources which belong to a package also update the package
+// descriptor, without changing or validating its other resources.
+func (r *Resource) Set(prop string, value interface{}) error {
+	return r.setProperties(map[string]interface{}{prop: value})
+}
+
+// SetName sets the resource name.
+func (r *Resource) SetName(name string) error {
+	return r.Set(nameProp, name)
+}
+
+// SetTitle sets the resource title. An empty title removes the property.
+func (r *Resource) SetTitle(title string) error {
+	return r.Set(titlePropName, title)
+}
+
+// SetDescription sets the resource description. An empty description removes the property.
+func (r *Resource) SetDescription(description string) error {
+	return r.Set(descriptionPropName, description)
+}
+
+// SetFormat sets the resource format. An empty format removes the property.
+func (r *Resource) SetFormat(format string) error {
+	return r.Set(formatProp, format)
+}
+
+// SetMediaType sets the resource mediatype. An empty mediatype removes the property.
+func (r *Resource) SetMediaType(mediaType string) error {
+	return r.Set(mediaTypeProp, mediaType)
+}
+
+// SetEncoding sets the resource character encoding. An empty encoding removes the property.
+func (r *Resource) SetEncoding(encoding string) error {
+	return r.Set(encodingProp, encoding)
+}
+
+// SetBytes sets the size of the resource file in bytes. Zero is stored as any other size:
+// use Set("bytes", nil) to remove the property.
+func (r *Resource) SetBytes(bytes int64) error {
+	// Stored as decoded descriptors store numbers. See FromReader.
+	return r.Set(bytesProp, json.Number(strconv.FormatInt(bytes, 10)))
+}
+
+// SetHash sets the resource file hash. An empty hash removes the property.
+func (r *Resource) SetHash(hash string) error {
+	return r.Set(hashProp, hash)
+}
+
+// SetPath sets the resource path. A single path is stored as a string, multiple paths
+// as a list.
+func (r *Resource) SetPath(path ...string) error {
+	if len(path) == 1 {
+		return r.Set(pathProp, path[0])
+	}
+	return r.Set(pathProp, stringsToList(path))
+}
+
+// SetLicenses sets the resource licenses. An empty list removes the property.
+func (r *Resource) SetLicenses(licenses ...License) error {
+	return r.Set(licensesPropName, licensesToList(licenses))
+}
+
+// SetSources sets the resource sources. An empty list removes the property.
+func (r *Resource) SetSources(sources ...Source) error {
+	return r.Set(sourcesPropName, sourcesToList(sources))
+}
+
+// setProperties sets the passed-in descriptor properties, removing the ones with empty values.
+// The resource is validated against the registry it was created with.
+func (r *Resource) setProperties(props map[string]interface{}) error {
+	d, err := clone.Descriptor(r.descriptor)
+	if err != nil {
+		return err
+	}
+	setProps(d, props)
+	res, err := newResource(d, r.basePath, r.SpecVersion(), r.registry, r.pkg == nil)
+	if err != nil {
+		return err
+	}
+	return r.replace(res)
+}
+
+// replace replaces the resource by the passed-in one, keeping its references, filled defaults,
+// error handling and package. The package the resource belongs to is updated accordingly.
+func (r *Resource) replace(res *Resource) error {
+	res.refs = unchangedRefs(res.refs, r.refs, r.descriptor, res.descriptor)
+	res.filled = keepDefaults(res.filled, r.filled, res.descriptor)
+	res.errPolicy = r.errPolicy
+	res.policySet = r.policySet
+	res.errHandler = r.errHandler
+	res.pkg = r.pkg
+	if r.pkg != nil {
+		if err := r.pkg.setResource(r.name, res); err != nil {
+			return err
+		}
+	}
+	*r = *res
+	return nil
+}
+
+// setResource replaces the named resource and its descriptor, leaving the rest of the package as is.
+// As done by AddResource, only the new resource is validated.
+func (p *Package) setResource(name string, res *Resource) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rSlice, ok := p.descriptor[resourcePropName].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid resources property:\"%v\"", p.descriptor[resourcePropName])
+	}
+	// Resources are kept in the same order as their descriptors.
+	for i, old := range p.resources {
+		if old.name != name {
+			continue
+		}
+		resDesc, err := clone.Descriptor(res.descriptor)
+		if err != nil {
+			return err
+		}
+		cpy := *res
+		cpy.pkg = p
+		cpy.errPolicy = old.errPolicy
+		cpy.policySet = old.policySet
+		cpy.errHandler = old.errHandler
+		descs := append(rSlice[:0:0], rSlice...)
+		descs[i] = resDesc
+		resources := append(p.resources[:0:0], p.resources...)
+		resources[i] = &cpy
+		p.descriptor = withProp(p.descriptor, resourcePropName, descs)
+		p.resources = resources
+		return nil
+	}
+	return fmt.Errorf("resource %s not found in the package", name)
+}
@@ -0,0 +1,99 @@
+package datapackage
+
+import (
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestResource_Metadata(t *testing.T) {
+	is := is.New(t)
+	r, err := NewResourceFromString(`{
+		"name": "res1",
+		"path": ["a.csv", "b.csv"],
+		"title": "Resource",
+		"description": "A resource",
+		"format": "csv",
+		"mediatype": "text/csv",
+		"encoding": "utf-8",
+		"bytes": 1024,
+		"hash": "sha256:abc",
+		"licenses": [{"name": "ODC-PDDL-1.0", "path": "http://opendatacommons.org/licenses/pddl/"}],
+		"sources": [{"title": "World Bank", "path": "http://data.worldbank.org"}]
+	}`, validator.MustInMemoryRegistry())
+	is.NoErr(err)
+	is.Equal(r.Title(), "Resource")
+	is.Equal(r.Description(), "A resource")
+	is.Equal(r.Format(), "csv")
+	is.Equal(r.MediaType(), "text/csv")
+	is.Equal(r.Encoding(), "utf-8")
+	is.Equal(r.Bytes(), int64(1024))
+	is.Equal(r.Hash(), "sha256:abc")
+	is.Equal(r.Path(), []string{"a.csv", "b.csv"})
+	is.Equal(r.Licenses(), []License{{Name: "ODC-PDDL-1.0", Path: "http://opendatacommons.org/licenses/pddl/"}})
+	is.Equal(r.Sources(), []Source{{Title: "World Bank", Path: "http://data.worldbank.org"}})
+}
+
+func TestResource_Set(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		r, err := NewResource(map[string]interface{}{"name": "res1", "path": "foo.csv"}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		is.NoErr(r.SetTitle("Foo"))
+		is.NoErr(r.SetBytes(42))
+		is.NoErr(r.SetPath("bar.csv", "baz.csv"))
+		is.NoErr(r.SetLicenses(License{Name: "MIT"}))
+		is.Equal(r.Title(), "Foo")
+		is.Equal(r.Bytes(), int64(42))
+		is.Equal(r.Path(), []string{"bar.csv", "baz.csv"})
+		is.Equal(r.Descriptor()["licenses"], []interface{}{map[string]interface{}{"name": "MIT"}})
+
+		is.NoErr(r.SetTitle(""))
+		_, ok := r.Descriptor()["title"]
+		is.True(!ok)
+
+		is.NoErr(r.SetBytes(0))
+		_, ok = r.Descriptor()["bytes"]
+		is.True(ok)
+		is.Equal(r.Bytes(), int64(0))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		is := is.New(t)
+		r, err := NewResource(map[string]interface{}{"name": "res1", "path": "foo.csv"}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		if err := r.SetName("Invalid Name"); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := r.Set("path", nil); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		is.Equal(r.Name(), "res1")
+		is.Equal(r.Path(), []string{"foo.csv"})
+	})
+	t.Run("WithinPackage", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		r := pkg.GetResource("res1")
+		is.NoErr(r.SetName("res2"))
+		is.NoErr(r.SetDescription("Renamed"))
+		is.Equal(r.Name(), "res2")
+		is.Equal(pkg.ResourceNames(), []string{"res2"})
+		is.Equal(pkg.GetResource("res2").Description(), "Renamed")
+	})
+	t.Run("UpdateWithinPackage", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1, r2}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		r := pkg.GetResource("res1").WithErrorPolicy(SkipInvalid, nil)
+		is.NoErr(r.Update(map[string]interface{}{"name": "res1", "path": "baz.csv"}, validator.InMemoryLoader()))
+		is.Equal(r.errPolicy, SkipInvalid)
+		is.Equal(pkg.GetResource("res1").Path(), []string{"baz.csv"})
+		is.Equal(pkg.GetResource("res1").errPolicy, FailFast)
+		is.Equal(pkg.ResourceNames(), []string{"res1", "res2"})
+		// The resource is still linked to the package.
+		is.NoErr(r.SetTitle("Bar"))
+		is.Equal(pkg.GetResource("res1").Title(), "Bar")
+	})
+}
@@ -5,7 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -186,12 +186,11 @@ func TestNew(t *testing.T) {
 		})
 		t.Run("ValidLocal", func(t *testing.T) {
 			is := is.New(t)
-			f, err := ioutil.TempFile("", "resourceNewValidLocal")
-			is.NoErr(err)
-			defer os.Remove(f.Name())
-			is.NoErr(ioutil.WriteFile(f.Name(), []byte(`{"fields": [{"name": "name","type": "string"}]}`), 0666))
+			// Resources created by NewResource accept absolute schema paths.
+			schPath := filepath.Join(t.TempDir(), "schema.json")
+			is.NoErr(ioutil.WriteFile(schPath, []byte(`{"fields": [{"name": "name","type": "string"}]}`), 0666))
 			r, err := NewResource(
-				map[string]interface{}{"name": "foo", "path": "foo.csv", "schema": f.Name()},
+				map[string]interface{}{"name": "foo", "path": "foo.csv", "schema": schPath},
 				validator.MustInMemoryRegistry(),
 			)
 			is.NoErr(err)
@@ -243,6 +242,21 @@ func TestResource_Update(t *testing.T) {
 		}
 	})
 }
+
+func TestResource_CustomProfile(t *testing.T) {
+	is := is.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"type": "object", "required": ["title"]}`)
+	}))
+	defer ts.Close()
+	_, err := NewResource(map[string]interface{}{"name": "foo", "path": "foo.csv", "title": "Foo", "profile": ts.URL}, validator.MustInMemoryRegistry())
+	is.NoErr(err)
+	_, err = NewResource(map[string]interface{}{"name": "foo", "path": "foo.csv", "profile": ts.URL}, validator.MustInMemoryRegistry())
+	is.True(err != nil) // Missing title.
+	_, err = NewResource(map[string]interface{}{"name": "foo", "title": "Foo", "profile": ts.URL}, validator.MustInMemoryRegistry())
+	is.True(err != nil) // Invalid against data-resource: missing path or data.
+}
+
 func TestResource_Tabular(t *testing.T) {
 	is := is.New(t)
 	r := NewUncheckedResource(map[string]interface{}{"profile": "tabular-data-resource"})
@@ -453,6 +467,124 @@ func TestResource_Cast(t *testing.T) {
 			t.Fatal("want:err got:nil")
 		}
 	})
+	t.Run("ErrorPolicy", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResourceFromString(`{
+			"name":    "iter",
+			"data":    "1,32\n2,foo\n1,33\n3,34",
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": {"fields": [{"name": "ID", "type": "integer"}, {"name": "Age", "type": "integer"}], "primaryKey": "ID"}
+		}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		var rows []struct{ ID, Age int }
+		// Without policy, all valid rows are cast and errors are reported as before.
+		err = res.Cast(&rows)
+		convErr, ok := err.(*schema.ConversionError)
+		if !ok {
+			t.Fatalf("want:*schema.ConversionError got:%v", err)
+		}
+		is.Equal(len(convErr.Errors), 2)
+		is.Equal(convErr.Errors[0].LineNumber, 1)
+		is.Equal(len(rows), 2)
+
+		err = res.WithErrorPolicy(FailFast, nil).Cast(&rows)
+		if _, ok := err.(*RowError); !ok {
+			t.Fatalf("want:*RowError got:%v", err)
+		}
+		is.Equal(len(rows), 1)
+
+		is.NoErr(res.WithErrorPolicy(SkipInvalid, nil).Cast(&rows))
+		is.Equal(len(rows), 2)
+		is.Equal(rows[1].ID, 3)
+
+		err = res.WithErrorPolicy(CollectErrors, nil).Cast(&rows)
+		rowErrs, ok := err.(RowErrors)
+		if !ok {
+			t.Fatalf("want:RowErrors got:%v", err)
+		}
+		is.Equal(len(rows), 2)
+		is.Equal(len(rowErrs), 2)
+		is.Equal(*rowErrs[0], RowError{Row: 1, Field: "Age", Value: "foo", Err: rowErrs[0].Err})
+		is.Equal(rowErrs[1].Row, 2) // Duplicated primary key.
+		is.Equal(rowErrs[1].Field, "ID")
+	})
+	t.Run("UniqueCastValues", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResourceFromString(`{
+			"name":    "iter",
+			"data":    "1,32\n01,33\n,34\n,35",
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": {"missingValues": [""], "fields": [{"name": "ID", "type": "integer", "constraints": {"unique": true}}, {"name": "Age", "type": "integer"}]}
+		}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		var rows []struct{ ID, Age int }
+		err = res.WithErrorPolicy(CollectErrors, nil).Cast(&rows)
+		rowErrs, ok := err.(RowErrors)
+		if !ok {
+			t.Fatalf("want:RowErrors got:%v", err)
+		}
+		// 1 and 01 are the same integer, missing values are not duplicates.
+		is.Equal(len(rowErrs), 1)
+		is.Equal(*rowErrs[0], RowError{Row: 1, Field: "ID", Value: "01", Err: rowErrs[0].Err})
+		is.Equal(len(rows), 3)
+	})
+	t.Run("CompositePrimaryKey", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResourceFromString(`{
+			"name":    "iter",
+			"data":    "1,a\n1,b\n01,a",
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": {"fields": [{"name": "A", "type": "integer"}, {"name": "B", "type": "string"}], "primaryKey": ["A", "B"]}
+		}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		var rows []struct {
+			A int
+			B string
+		}
+		err = res.WithErrorPolicy(CollectErrors, nil).Cast(&rows)
+		rowErrs, ok := err.(RowErrors)
+		if !ok {
+			t.Fatalf("want:RowErrors got:%v", err)
+		}
+		// Only the whole key must be unique: 01,a duplicates 1,a.
+		is.Equal(len(rowErrs), 1)
+		is.Equal(*rowErrs[0], RowError{Row: 2, Field: "A,B", Value: "01,a", Err: rowErrs[0].Err})
+		is.Equal(len(rows), 2)
+	})
+	t.Run("FieldMissingValues", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResourceFromString(`{
+			"name":    "iter",
+			"data":    "NA,32\n1,",
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": {"missingValues": [""], "fields": [{"name": "ID", "type": "integer", "missingValues": ["NA"]}, {"name": "Age", "type": "integer"}]}
+		}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		var rows []struct{ ID, Age int }
+		is.NoErr(res.Cast(&rows))
+		is.Equal(rows, []struct{ ID, Age int }{{0, 32}, {1, 0}})
+
+		// Missing values of required fields are invalid, as done by Rows.
+		res, err = NewResourceFromString(`{
+			"name":    "iter",
+			"data":    "1,32\n2,",
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": {"missingValues": [""], "fields": [{"name": "ID", "type": "integer"}, {"name": "Age", "type": "integer", "constraints": {"required": true}}]}
+		}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		err = res.WithErrorPolicy(FailFast, nil).Cast(&rows)
+		rowErr, ok := err.(*RowError)
+		if !ok {
+			t.Fatalf("want:*RowError got:%v", err)
+		}
+		is.Equal(rowErr.Row, 1)
+		is.Equal(rowErr.Field, "Age")
+	})
 }
 
 func TestResource_RawRead(t *testing.T) {
@@ -554,4 +686,25 @@ func TestResource_ReadColumn(t *testing.T) {
 			t.Fatal("want:err got:nil")
 		}
 	})
+	t.Run("ErrorPolicy", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResourceFromString(`
+			{
+				"name":    "col",
+				"data":    "name,age\nfoo,42\nbar,baz\nboo,84",
+				"format":  "csv",
+				"profile": "tabular-data-resource",
+				"schema": {"fields": [{"name": "name", "type": "string"},{"name": "age", "type": "integer"}]}
+			}`, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		var ages []float64
+		if _, ok := res.CastColumn("age", &ages, csv.LoadHeaders()).(*RowError); !ok {
+			t.Fatal("want:*RowError")
+		}
+		var handled []*RowError
+		is.NoErr(res.WithErrorPolicy(SkipInvalid, func(e *RowError) { handled = append(handled, e) }).CastColumn("age", &ages, csv.LoadHeaders()))
+		is.Equal(ages, []float64{42, 84})
+		is.Equal(len(handled), 1)
+		is.Equal(*handled[0], RowError{Row: 1, Field: "age", Value: "baz", Err: handled[0].Err})
+	})
 }
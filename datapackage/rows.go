@@ -0,0 +1,433 @@
+package datapackage
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/frictionlessdata/tableschema-go/csv"
+	"github.com/frictionlessdata/tableschema-go/schema"
+	"github.com/frictionlessdata/tableschema-go/table"
+)
+
+// RowError is returned when a row can not be cast to the resource schema.
+type RowError struct {
+	Row   int    // Zero-based index of the row within the table.
+	Field string // Name of the schema field which could not be cast, if known.
+	Value string // Raw value which could not be cast, if Field is known.
+	Err   error
+}
+
+func (e *RowError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("row %d, field %s (value:%q): %v", e.Row, e.Field, e.Value, e.Err)
+	}
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// RowErrors is returned when rows could not be cast using the CollectErrors policy.
+type RowErrors []*RowError
+
+func (e RowErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, rowErr := range e {
+		msgs[i] = rowErr.Error()
+	}
+	return fmt.Sprintf("%d invalid rows: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// ErrorPolicy defines how rows which can not be cast to the resource schema are handled.
+type ErrorPolicy int
+
+const (
+	// FailFast stops casting at the first invalid row, returning its *RowError. This is the default,
+	// but for Resource.Cast (see its documentation).
+	FailFast ErrorPolicy = iota
+	// SkipInvalid skips invalid rows, without reporting any error.
+	SkipInvalid
+	// CollectErrors skips invalid rows, reporting all their errors as RowErrors once all rows are cast.
+	CollectErrors
+)
+
+// WithErrorPolicy returns a copy of the resource which handles invalid rows according to the
+// passed-in policy when casting (Cast, CastColumn and Rows). If not nil, the handler is called
+// for each invalid row, regardless of the policy.
+func (r *Resource) WithErrorPolicy(policy ErrorPolicy, handler func(*RowError)) *Resource {
+	cpy := *r
+	cpy.errPolicy = policy
+	cpy.policySet = true
+	cpy.errHandler = handler
+	return &cpy
+}
+
+func (r *Resource) rowErrorHandling() *rowErrorHandling {
+	return &rowErrorHandling{policy: r.errPolicy, handler: r.errHandler}
+}
+
+// rowErrorHandling applies an error policy to the rows of a single casting.
+type rowErrorHandling struct {
+	policy  ErrorPolicy
+	handler func(*RowError)
+	errs    RowErrors
+}
+
+// handle reports the invalid row, returning an error if casting must stop.
+func (h *rowErrorHandling) handle(e *RowError) error {
+	if h.handler != nil {
+		h.handler(e)
+	}
+	switch h.policy {
+	case SkipInvalid:
+		return nil
+	case CollectErrors:
+		h.errs = append(h.errs, e)
+		return nil
+	}
+	return e
+}
+
+// err returns the errors collected once casting is finished.
+func (h *rowErrorHandling) err() error {
+	if len(h.errs) > 0 {
+		return h.errs
+	}
+	return nil
+}
+
+// castRow casts the row into out, which must be a pointer to a struct, returning the cast values.
+// Values are cast as done by castToRow, so both agree on which values are missing. If the row
+// can not be cast, the returned error reports the first value which could not be cast, if any.
+func castRow(sch *schema.Schema, index int, row []string, out interface{}) ([]interface{}, *RowError) {
+	values, rowErr := castValues(sch, index, row)
+	if rowErr != nil {
+		return nil, rowErr
+	}
+	// schema.CastRow only knows the schema missing values: missing values are replaced by a value
+	// which is not part of the row, which becomes the only schema missing value.
+	marker := "\x00"
+	for contains(row, marker) {
+		marker += "\x00"
+	}
+	cpy := *sch
+	cpy.MissingValues = []string{marker}
+	r := append([]string{}, row...)
+	for i, v := range values {
+		if v == nil {
+			r[i] = marker
+		}
+	}
+	if err := cpy.CastRow(r, out); err != nil {
+		return nil, &RowError{Row: index, Err: err}
+	}
+	return values, nil
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isMissingValue(sch *schema.Schema, v string) bool {
+	for _, mv := range sch.MissingValues {
+		if mv == v {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueChecker checks the values of unique fields and primary keys are not duplicated. Cast
+// values are compared, so "1" and "01" are duplicated integers. Composite primary keys are
+// checked as a whole. Missing values are not checked.
+type uniqueChecker struct {
+	sch     *schema.Schema
+	indexes []int // Unique fields.
+	pk      []int // Primary key fields.
+	seen    map[uniqueValue]struct{}
+}
+
+// uniqueValue is a value of a unique field, or the values of the primary key (index -1).
+type uniqueValue struct {
+	index int
+	value interface{}
+}
+
+func newUniqueChecker(sch *schema.Schema) *uniqueChecker {
+	u := uniqueChecker{sch: sch, seen: make(map[uniqueValue]struct{})}
+	for i, f := range sch.Fields {
+		if f.Constraints.Unique {
+			u.indexes = append(u.indexes, i)
+		}
+	}
+	for _, name := range sch.PrimaryKeys {
+		if _, pos := sch.GetField(name); pos != schema.InvalidPosition {
+			u.pk = append(u.pk, pos)
+		}
+	}
+	return &u
+}
+
+// check returns an error if the row duplicates unique values of previously checked rows. Values
+// are only recorded if the row is valid.
+func (u *uniqueChecker) check(index int, row []string, values []interface{}) *RowError {
+	for _, i := range u.indexes {
+		if values[i] == nil {
+			continue
+		}
+		if _, ok := u.seen[uniqueValue{i, uniqueKey(values[i])}]; ok {
+			return &RowError{Row: index, Field: u.sch.Fields[i].Name, Value: row[i], Err: fmt.Errorf("duplicate value")}
+		}
+	}
+	pk, hasPK := u.primaryKey(values)
+	if hasPK {
+		if _, ok := u.seen[pk]; ok {
+			names, raw := make([]string, len(u.pk)), make([]string, len(u.pk))
+			for j, i := range u.pk {
+				names[j], raw[j] = u.sch.Fields[i].Name, row[i]
+			}
+			return &RowError{Row: index, Field: strings.Join(names, ","), Value: strings.Join(raw, ","), Err: fmt.Errorf("duplicate primary key")}
+		}
+	}
+	for _, i := range u.indexes {
+		if values[i] != nil {
+			u.seen[uniqueValue{i, uniqueKey(values[i])}] = struct{}{}
+		}
+	}
+	if hasPK {
+		u.seen[pk] = struct{}{}
+	}
+	return nil
+}
+
+// primaryKey returns the primary key of the row, if the schema has one and none of its values is missing.
+func (u *uniqueChecker) primaryKey(values []interface{}) (uniqueValue, bool) {
+	if len(u.pk) == 0 {
+		return uniqueValue{}, false
+	}
+	keys := make([]interface{}, len(u.pk))
+	for j, i := range u.pk {
+		if values[i] == nil {
+			return uniqueValue{}, false
+		}
+		keys[j] = uniqueKey(values[i])
+	}
+	return uniqueValue{-1, fmt.Sprintf("%#v", keys)}, true
+}
+
+// uniqueKey returns the cast value, if it can be used as a map key, or its representation otherwise
+// (e.g. objects and arrays).
+func uniqueKey(v interface{}) interface{} {
+	if reflect.TypeOf(v).Comparable() {
+		return v
+	}
+	return fmt.Sprintf("%T:%v", v, v)
+}
+
+// Row is a row of a tabular resource, holding its values cast to the resource schema. Missing
+// values are nil.
+type Row struct {
+	fields *rowFields
+	values []interface{}
+}
+
+// rowFields holds the field names of rows, shared by all rows of a table.
+type rowFields struct {
+	names []string
+	index map[string]int
+}
+
+func newRowFields(sch *schema.Schema) *rowFields {
+	f := rowFields{names: make([]string, len(sch.Fields)), index: make(map[string]int, len(sch.Fields))}
+	for i, field := range sch.Fields {
+		f.names[i] = field.Name
+		if _, ok := f.index[field.Name]; !ok {
+			f.index[field.Name] = i
+		}
+	}
+	return &f
+}
+
+// Fields returns the names of the row fields, in the schema order.
+func (r Row) Fields() []string {
+	if r.fields == nil {
+		return nil
+	}
+	return append([]string{}, r.fields.names...)
+}
+
+// Values returns the row values, in the schema order.
+func (r Row) Values() []interface{} {
+	return append([]interface{}{}, r.values...)
+}
+
+// Get returns the value of the field. The boolean is false if the schema has no such field.
+func (r Row) Get(field string) (interface{}, bool) {
+	if r.fields == nil {
+		return nil, false
+	}
+	i, ok := r.fields.index[field]
+	if !ok {
+		return nil, false
+	}
+	return r.values[i], true
+}
+
+// Map returns the row values keyed by field name.
+func (r Row) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(r.values))
+	for i, v := range r.values {
+		if _, ok := m[r.fields.names[i]]; !ok {
+			m[r.fields.names[i]] = v
+		}
+	}
+	return m
+}
+
+// castToRow casts each value of the row according to its schema field.
+func castToRow(sch *schema.Schema, fields *rowFields, index int, row []string) (Row, *RowError) {
+	values, rowErr := castValues(sch, index, row)
+	if rowErr != nil {
+		return Row{}, rowErr
+	}
+	return Row{fields: fields, values: values}, nil
+}
+
+// castValues casts each value of the row according to its schema field. Missing values of
+// fields which are not required are nil.
+func castValues(sch *schema.Schema, index int, row []string) ([]interface{}, *RowError) {
+	if len(row) != len(sch.Fields) {
+		return nil, &RowError{Row: index, Err: fmt.Errorf("the row with %d values does not match the %d fields in the schema", len(row), len(sch.Fields))}
+	}
+	values := make([]interface{}, len(row))
+	for i := range sch.Fields {
+		f := &sch.Fields[i]
+		if isFieldMissingValue(sch, f, row[i]) && !f.Constraints.Required {
+			continue
+		}
+		v, err := f.Cast(row[i])
+		if err != nil {
+			return nil, &RowError{Row: index, Field: f.Name, Value: row[i], Err: err}
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// isFieldMissingValue reports whether the value is missing, using the field missing values if
+// the schema declares them (Table Schema v2).
+func isFieldMissingValue(sch *schema.Schema, f *schema.Field, v string) bool {
+	if f.MissingValues != nil {
+		_, ok := f.MissingValues[v]
+		return ok
+	}
+	return isMissingValue(sch, v)
+}
+
+// RowIterator iterates over the rows of a tabular resource, casting each one of them to T.
+// Rows are read and cast lazily, so resources of any size can be processed.
+type RowIterator[T any] struct {
+	iter  table.Iterator
+	cast  func(index int, row []string) (T, *RowError)
+	errs  *rowErrorHandling
+	index int
+	row   T
+	err   error
+}
+
+// Rows returns an iterator over the resource rows, which are cast through the resource schema
+// into T. T can be:
+//   - Row, holding the values cast according to their schema fields, in the schema order.
+//   - map[string]interface{}, holding the same values as Row, keyed by field name.
+//   - a struct type, whose fields are matched to the schema fields the same way Resource.Cast does.
+//
+// Row and map[string]interface{} allow processing any tabular resource, without knowing its
+// schema in advance. Unlike Resource.Cast, unique constraints are not checked.
+//
+// Rows which can not be cast are handled according to the resource error policy (see
+// WithErrorPolicy). By default, the iteration stops at the first invalid row, which is reported
+// by the iterator Err method as a *RowError.
+func Rows[T any](r *Resource, opts ...csv.CreationOpts) (*RowIterator[T], error) {
+	sch, err := r.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+	var cast func(index int, row []string) (T, *RowError)
+	var zero T
+	switch interface{}(zero).(type) {
+	case Row:
+		fields := newRowFields(&sch)
+		cast = func(index int, row []string) (T, *RowError) {
+			v, err := castToRow(&sch, fields, index, row)
+			return interface{}(v).(T), err
+		}
+	case map[string]interface{}:
+		fields := newRowFields(&sch)
+		cast = func(index int, row []string) (T, *RowError) {
+			v, err := castToRow(&sch, fields, index, row)
+			if err != nil {
+				return zero, err
+			}
+			return interface{}(v.Map()).(T), nil
+		}
+	default:
+		if t := reflect.TypeOf(zero); t == nil || t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("rows can only be cast to Row, map[string]interface{} or structs. got:%T", zero)
+		}
+		cast = func(index int, row []string) (T, *RowError) {
+			var v T
+			_, err := castRow(&sch, index, row, &v)
+			return v, err
+		}
+	}
+	iter, err := r.Iter(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &RowIterator[T]{iter: iter, cast: cast, errs: r.rowErrorHandling(), index: -1}, nil
+}
+
+// Next advances the iterator to the next row, which will be available through the Row method.
+// It returns false when the iteration stops, either by reaching the end of the table or an error.
+func (it *RowIterator[T]) Next() bool {
+	for it.err == nil && it.iter.Next() {
+		it.index++
+		row, rowErr := it.cast(it.index, it.iter.Row())
+		if rowErr != nil {
+			it.err = it.errs.handle(rowErr)
+			continue
+		}
+		it.row = row
+		return true
+	}
+	return false
+}
+
+// Row returns the most recent row fetched by a call to Next.
+func (it *RowIterator[T]) Row() T {
+	return it.row
+}
+
+// Err returns the error which stopped the iteration, if any. Using the CollectErrors policy,
+// the errors of all invalid rows are returned once the iteration is finished.
+func (it *RowIterator[T]) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+	if err := it.iter.Err(); err != nil {
+		return err
+	}
+	return it.errs.err()
+}
+
+// Close frees the resources associated to the iterator.
+func (it *RowIterator[T]) Close() error {
+	return it.iter.Close()
+}
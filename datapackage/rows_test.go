@@ -0,0 +1,227 @@
+package datapackage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/frictionlessdata/tableschema-go/csv"
+	"github.com/frictionlessdata/tableschema-go/schema"
+	"github.com/matryer/is"
+)
+
+type person struct {
+	Name string `tableheader:"name"`
+	Age  int    `tableheader:"age"`
+}
+
+func TestRows(t *testing.T) {
+	resource := func(t *testing.T, data string) *Resource {
+		res, err := NewResource(map[string]interface{}{
+			"name":    "people",
+			"data":    data,
+			"format":  "csv",
+			"profile": "tabular-data-resource",
+			"schema": map[string]interface{}{"fields": []interface{}{
+				map[string]interface{}{"name": "name", "type": "string"},
+				map[string]interface{}{"name": "age", "type": "integer"},
+			}},
+		}, validator.MustInMemoryRegistry())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		rows, err := Rows[person](resource(t, "name,age\nfoo,32\nbar,45\n"), csv.LoadHeaders())
+		is.NoErr(err)
+		defer rows.Close()
+		var got []person
+		for rows.Next() {
+			got = append(got, rows.Row())
+		}
+		is.NoErr(rows.Err())
+		is.Equal(got, []person{{"foo", 32}, {"bar", 45}})
+	})
+	t.Run("CastError", func(t *testing.T) {
+		is := is.New(t)
+		rows, err := Rows[person](resource(t, "foo,32\nbar,baz\nboo,33"))
+		is.NoErr(err)
+		defer rows.Close()
+		is.True(rows.Next())
+		is.Equal(rows.Row(), person{"foo", 32})
+		is.True(!rows.Next())
+		is.True(!rows.Next())
+		var rowErr *RowError
+		if !errors.As(rows.Err(), &rowErr) {
+			t.Fatalf("want:*RowError got:%v", rows.Err())
+		}
+		is.Equal(rowErr.Row, 1)
+	})
+	t.Run("NotStruct", func(t *testing.T) {
+		if _, err := Rows[[]string](resource(t, "foo,32")); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if _, err := Rows[*person](resource(t, "foo,32")); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("NoSchema", func(t *testing.T) {
+		if _, err := Rows[person](NewUncheckedResource(map[string]interface{}{})); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("NotTabular", func(t *testing.T) {
+		res := NewUncheckedResource(map[string]interface{}{"schema": map[string]interface{}{}})
+		if _, err := Rows[person](res); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
+
+func TestRows_ErrorPolicy(t *testing.T) {
+	res, err := NewResource(map[string]interface{}{
+		"name":    "people",
+		"data":    "foo,32\nbar,baz\nboo,33\nqux,quux",
+		"format":  "csv",
+		"profile": "tabular-data-resource",
+		"schema": map[string]interface{}{"fields": []interface{}{
+			map[string]interface{}{"name": "name", "type": "string"},
+			map[string]interface{}{"name": "age", "type": "integer"},
+		}},
+	}, validator.MustInMemoryRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	readAll := func(res *Resource) ([]person, error) {
+		rows, err := Rows[person](res)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var got []person
+		for rows.Next() {
+			got = append(got, rows.Row())
+		}
+		return got, rows.Err()
+	}
+	t.Run("FailFast", func(t *testing.T) {
+		is := is.New(t)
+		var handled []*RowError
+		got, err := readAll(res.WithErrorPolicy(FailFast, func(e *RowError) { handled = append(handled, e) }))
+		is.Equal(got, []person{{"foo", 32}})
+		is.Equal(err, handled[0])
+		is.Equal(len(handled), 1)
+		is.Equal(handled[0].Row, 1)
+		is.Equal(handled[0].Field, "age")
+		is.Equal(handled[0].Value, "baz")
+	})
+	t.Run("SkipInvalid", func(t *testing.T) {
+		is := is.New(t)
+		var handled []*RowError
+		got, err := readAll(res.WithErrorPolicy(SkipInvalid, func(e *RowError) { handled = append(handled, e) }))
+		is.NoErr(err)
+		is.Equal(got, []person{{"foo", 32}, {"boo", 33}})
+		is.Equal(len(handled), 2)
+		is.Equal(handled[1].Row, 3)
+	})
+	t.Run("CollectErrors", func(t *testing.T) {
+		is := is.New(t)
+		got, err := readAll(res.WithErrorPolicy(CollectErrors, nil))
+		is.Equal(got, []person{{"foo", 32}, {"boo", 33}})
+		rowErrs, ok := err.(RowErrors)
+		if !ok {
+			t.Fatalf("want:RowErrors got:%v", err)
+		}
+		is.Equal(len(rowErrs), 2)
+		is.Equal(rowErrs[0].Value, "baz")
+		is.Equal(rowErrs[1].Value, "quux")
+	})
+	t.Run("KeepsOriginal", func(t *testing.T) {
+		is := is.New(t)
+		res.WithErrorPolicy(SkipInvalid, nil)
+		_, err := readAll(res)
+		if _, ok := err.(*RowError); !ok {
+			t.Fatalf("want:*RowError got:%v", err)
+		}
+		is.Equal(res.errPolicy, FailFast)
+	})
+}
+
+func TestRows_Row(t *testing.T) {
+	res, err := NewResource(map[string]interface{}{
+		"name":    "cities",
+		"data":    "city,population,area,capital,location\nrome,2860000,1285.5,1871-07-01,\"12.5,41.9\"\nlondon,,1572,0047-01-01,\"51.5,-0.1\"",
+		"format":  "csv",
+		"profile": "tabular-data-resource",
+		"schema": map[string]interface{}{
+			"missingValues": []interface{}{""},
+			"fields": []interface{}{
+				map[string]interface{}{"name": "city", "type": "string"},
+				map[string]interface{}{"name": "population", "type": "integer"},
+				map[string]interface{}{"name": "area", "type": "number"},
+				map[string]interface{}{"name": "capital", "type": "date"},
+				map[string]interface{}{"name": "location", "type": "geopoint"},
+			},
+		},
+	}, validator.MustInMemoryRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("Row", func(t *testing.T) {
+		is := is.New(t)
+		rows, err := Rows[Row](res, csv.LoadHeaders())
+		is.NoErr(err)
+		defer rows.Close()
+		var got []Row
+		for rows.Next() {
+			got = append(got, rows.Row())
+		}
+		is.NoErr(rows.Err())
+		is.Equal(len(got), 2)
+		is.Equal(got[0].Fields(), []string{"city", "population", "area", "capital", "location"})
+		is.Equal(got[0].Values(), []interface{}{"rome", int64(2860000), 1285.5, time.Date(1871, 7, 1, 0, 0, 0, 0, time.UTC), schema.GeoPoint{Lon: 12.5, Lat: 41.9}})
+
+		v, ok := got[1].Get("population")
+		is.True(ok)
+		is.Equal(v, nil) // Missing value.
+		v, ok = got[1].Get("area")
+		is.True(ok)
+		is.Equal(v, float64(1572))
+		_, ok = got[1].Get("foo")
+		is.True(!ok)
+	})
+	t.Run("Map", func(t *testing.T) {
+		is := is.New(t)
+		rows, err := Rows[map[string]interface{}](res, csv.LoadHeaders())
+		is.NoErr(err)
+		defer rows.Close()
+		is.True(rows.Next())
+		is.Equal(rows.Row()["city"], "rome")
+		is.Equal(rows.Row()["population"], int64(2860000))
+		is.Equal(len(rows.Row()), 5)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		is := is.New(t)
+		rows, err := Rows[Row](res)
+		is.NoErr(err)
+		defer rows.Close()
+		is.True(!rows.Next())
+		rowErr, ok := rows.Err().(*RowError)
+		if !ok {
+			t.Fatalf("want:*RowError got:%v", rows.Err())
+		}
+		is.Equal(rowErr.Field, "population") // Header row.
+		is.Equal(rowErr.Value, "population")
+	})
+	t.Run("ZeroRow", func(t *testing.T) {
+		is := is.New(t)
+		var r Row
+		_, ok := r.Get("foo")
+		is.True(!ok)
+		is.Equal(len(r.Fields()), 0)
+		is.Equal(len(r.Map()), 0)
+	})
+}
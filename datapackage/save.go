@@ -0,0 +1,248 @@
+package datapackage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+	"github.com/frictionlessdata/datapackage-go/validator"
+)
+
+// InlineDataToFiles makes Package.SaveTo save inline resource data as files in the data
+// directory, changing the resources to point at them. It is ignored when saving descriptors.
+func InlineDataToFiles() SaveOption {
+	return func(o *saveOptions) {
+		o.dataToFiles = true
+	}
+}
+
+// InlineFiles makes Package.SaveTo inline the contents of CSV and JSON resource files which are
+// not bigger than maxBytes, instead of saving them as files. Multipart resources are never
+// inlined. It is ignored when saving descriptors.
+func InlineFiles(maxBytes int64) SaveOption {
+	return func(o *saveOptions) {
+		o.inlineFiles = maxBytes
+	}
+}
+
+// SaveTo saves the package, data included, into the passed-in directory. Resource files keep
+// their relative paths, local ones are copied and remote ones are downloaded to the data
+// directory. Zip-loaded packages are saved as plain directory trees.
+//
+// Files are first gathered in a temporary directory, so the resulting descriptor is validated
+// before the passed-in directory is changed. The descriptor is saved last as datapackage.json,
+// with schemas and dialects inline. It replaces any previous descriptor atomically, so the
+// directory never holds a partially written descriptor (see SaveDescriptor and FileMode).
+// Unlike Fetch, SaveTo does not change the package.
+func (p *Package) SaveTo(dir string, opts ...SaveOption) error {
+	o := newSaveOptions(opts)
+	o.inline = true
+	p.mu.RLock()
+	d, resources, reg, layout, filled := p.descriptor, p.resources, p.valRegistry, p.layout, p.filled
+	p.mu.RUnlock()
+
+	jobs, paths, err := fetchJobs(resources)
+	if err != nil {
+		return err
+	}
+	staging, err := ioutil.TempDir("", "datapackage_saveto")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	fo := fetchOptions{workers: defaultFetchWorkers, client: http.DefaultClient}
+	if err := runFetchJobs(context.Background(), staging, jobs, fo); err != nil {
+		return err
+	}
+	// Saved files, and whether they were saved onto their sources.
+	saved := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		saved[job.dst] = !job.remote && samePath(job.src, filepath.Join(dir, filepath.FromSlash(job.dst)))
+	}
+
+	rCopy, err := clone.Value(d[resourcePropName])
+	if err != nil {
+		return err
+	}
+	newDesc := withProp(d, resourcePropName, rCopy)
+	rSlice, _ := newDesc[resourcePropName].([]interface{})
+	for i, r := range resources {
+		rMap, ok := rSlice[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if paths[i] != nil {
+			setPaths(rMap, paths[i])
+		}
+		switch {
+		case r.data != nil && o.dataToFiles:
+			dst := inlineDataPath(resourceFileName(r.name, i), rMap)
+			if _, ok := saved[dst]; ok {
+				return fmt.Errorf("resource %s: data would be saved to the path of another resource (%s)", r.name, dst)
+			}
+			if err := saveInlineData(staging, dst, rMap); err != nil {
+				return fmt.Errorf("resource %s: %w", r.name, err)
+			}
+			saved[dst] = false
+		case o.inlineFiles > 0 && len(r.path) == 1:
+			if err := inlineFile(staging, rMap, o.inlineFiles); err != nil {
+				return fmt.Errorf("resource %s: %w", r.name, err)
+			}
+		}
+	}
+
+	newP, err := New(newDesc, dir, func() (validator.Registry, error) { return reg, nil })
+	if err != nil {
+		return err
+	}
+	newP.layout = layout
+	newP.filled = keepDefaults(newP.filled, filled, newP.descriptor)
+	keepResourceDefaults(newP.resources, resources)
+	var buf bytes.Buffer
+	if err := newP.write(&buf, o); err != nil {
+		return err
+	}
+
+	// Moving the files which are still referenced, unless they were saved onto themselves.
+	for _, rMap := range rSlice {
+		if rMap, ok := rMap.(map[string]interface{}); ok {
+			for _, p := range resourcePaths(rMap) {
+				if isSource, ok := saved[p]; ok && !isSource {
+					if err := moveFile(filepath.Join(staging, filepath.FromSlash(p)), filepath.Join(dir, filepath.FromSlash(p))); err != nil {
+						return err
+					}
+					saved[p] = true // Files shared by resources are only moved once.
+				}
+			}
+		}
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	descriptorPath := filepath.Join(dir, descriptorFileNameWithinZip)
+	_, err = writeFileAtomic(descriptorPath, &buf, descriptorFileMode(descriptorPath, o))
+	return err
+}
+
+// moveFile moves the file, copying it if it can not be renamed (e.g. across file systems).
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = writeFile(dst, f)
+	return err
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// resourcePaths returns the paths of the resource descriptor.
+func resourcePaths(rMap map[string]interface{}) []string {
+	switch p := rMap[pathProp].(type) {
+	case string:
+		return []string{p}
+	case []interface{}:
+		var paths []string
+		for _, s := range p {
+			if s, ok := s.(string); ok {
+				paths = append(paths, s)
+			}
+		}
+		return paths
+	}
+	return nil
+}
+
+// inlineDataPath returns the path, within the data directory, inline resource data is saved to.
+func inlineDataPath(name string, rMap map[string]interface{}) string {
+	ext := "json"
+	if _, ok := rMap[dataProp].(string); ok {
+		ext = "txt"
+		if f, ok := rMap[formatProp].(string); ok && f != "" && !strings.ContainsAny(f, `/\.`) {
+			ext = f
+		}
+	}
+	return path.Join(fetchDataDir, name+"."+ext)
+}
+
+// saveInlineData saves the resource inline data to the file, changing the resource to point at it.
+func saveInlineData(dir, dst string, rMap map[string]interface{}) error {
+	contents, ok := rMap[dataProp].(string)
+	if !ok {
+		b, err := json.Marshal(rMap[dataProp])
+		if err != nil {
+			return err
+		}
+		contents = string(b)
+	}
+	if _, err := writeFile(filepath.Join(dir, filepath.FromSlash(dst)), strings.NewReader(contents)); err != nil {
+		return err
+	}
+	delete(rMap, dataProp)
+	rMap[pathProp] = dst
+	return nil
+}
+
+// inlineFile inlines the contents of the resource file, if it is a small enough CSV or JSON file.
+func inlineFile(dir string, rMap map[string]interface{}, maxBytes int64) error {
+	paths := resourcePaths(rMap)
+	if len(paths) != 1 {
+		return nil
+	}
+	ext := strings.ToLower(path.Ext(paths[0]))
+	if ext != ".csv" && ext != ".json" {
+		return nil
+	}
+	p := filepath.Join(dir, filepath.FromSlash(paths[0]))
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if info.Size() > maxBytes {
+		return nil
+	}
+	contents, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	if ext == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(contents))
+		dec.UseNumber()
+		var data interface{}
+		if err := dec.Decode(&data); err != nil {
+			return fmt.Errorf("error decoding %s: %w", paths[0], err)
+		}
+		switch data.(type) {
+		case []interface{}, map[string]interface{}:
+		default: // Only arrays and objects can be inlined as they are.
+			return nil
+		}
+		rMap[dataProp] = data
+	} else {
+		rMap[dataProp] = string(contents)
+		if rMap[formatProp] == nil && rMap[mediaTypeProp] == nil {
+			rMap[formatProp] = "csv"
+		}
+	}
+	delete(rMap, pathProp)
+	return nil
+}
@@ -0,0 +1,173 @@
+package datapackage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestPackage_SaveTo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "name\nremote")
+	}))
+	defer ts.Close()
+	src, err := ioutil.TempDir("", "datapackage_saveto_src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	if err := ioutil.WriteFile(filepath.Join(src, "local.csv"), []byte("name\nlocal"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	descriptor := fmt.Sprintf(`{"resources": [
+		{"name": "local", "path": "local.csv", "schema": {"fields": [{"name": "name", "type": "string"}]}},
+		{"name": "remote", "path": "%s/remote.csv"},
+		{"name": "csv", "data": "name\ninline", "format": "csv"},
+		{"name": "json", "data": [{"name": "inline"}]}
+	]}`, ts.URL)
+	newPkg := func(t *testing.T) *Package {
+		pkg, err := FromString(descriptor, src, validator.InMemoryLoader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pkg
+	}
+	tempDir := func(t *testing.T) string {
+		dir, err := ioutil.TempDir("", "datapackage_saveto")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	readFile := func(t *testing.T, p string) string {
+		buf, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		pkg := newPkg(t)
+		is.NoErr(pkg.SaveTo(dir))
+		is.Equal(readFile(t, filepath.Join(dir, "local.csv")), "name\nlocal")
+		is.Equal(readFile(t, filepath.Join(dir, "data", "remote", "remote.csv")), "name\nremote")
+
+		saved, err := Load(filepath.Join(dir, "datapackage.json"), validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(saved.GetResource("remote").Descriptor()["path"], "data/remote/remote.csv")
+		is.Equal(saved.GetResource("csv").Descriptor()["data"], "name\ninline")
+		contents, err := saved.GetResource("remote").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"name"}, {"remote"}})
+
+		// The package is not changed.
+		is.Equal(pkg.GetResource("remote").Descriptor()["path"], ts.URL+"/remote.csv")
+		files, err := ioutil.ReadDir(dir)
+		is.NoErr(err)
+		is.Equal(len(files), 3) // datapackage.json, local.csv and data.
+	})
+	t.Run("InlineDataToFiles", func(t *testing.T) {
+		is := is.New(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		is.NoErr(newPkg(t).SaveTo(dir, InlineDataToFiles()))
+		is.Equal(readFile(t, filepath.Join(dir, "data", "csv.csv")), "name\ninline")
+		is.Equal(readFile(t, filepath.Join(dir, "data", "json.json")), `[{"name":"inline"}]`)
+
+		saved, err := Load(filepath.Join(dir, "datapackage.json"), validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(saved.GetResource("csv").Descriptor()["path"], "data/csv.csv")
+		is.Equal(saved.GetResource("csv").Descriptor()["data"], nil)
+		contents, err := saved.GetResource("csv").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"name"}, {"inline"}})
+	})
+	t.Run("InlineFiles", func(t *testing.T) {
+		is := is.New(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		is.NoErr(newPkg(t).SaveTo(dir, InlineFiles(1024)))
+		saved, err := Load(filepath.Join(dir, "datapackage.json"), validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(saved.GetResource("local").Descriptor()["data"], "name\nlocal")
+		is.Equal(saved.GetResource("local").Descriptor()["format"], "csv")
+		is.Equal(saved.GetResource("remote").Descriptor()["data"], "name\nremote")
+		_, err = os.Stat(filepath.Join(dir, "local.csv"))
+		is.True(os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(dir, "data", "remote", "remote.csv"))
+		is.True(os.IsNotExist(err))
+
+		// Files bigger than the limit are kept.
+		is.NoErr(newPkg(t).SaveTo(dir, InlineFiles(5)))
+		is.Equal(readFile(t, filepath.Join(dir, "local.csv")), "name\nlocal")
+	})
+	t.Run("SameDirectory", func(t *testing.T) {
+		is := is.New(t)
+		is.NoErr(newPkg(t).SaveTo(src, InlineFiles(1024)))
+		defer os.Remove(filepath.Join(src, "datapackage.json"))
+		defer os.RemoveAll(filepath.Join(src, "data"))
+		is.Equal(readFile(t, filepath.Join(src, "local.csv")), "name\nlocal")
+	})
+	t.Run("Zip", func(t *testing.T) {
+		is := is.New(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		pkg, err := Load("test_package.zip", validator.InMemoryLoader())
+		is.NoErr(err)
+		is.NoErr(pkg.SaveTo(dir))
+		saved, err := Load(filepath.Join(dir, "datapackage.json"), validator.InMemoryLoader())
+		is.NoErr(err)
+		contents, err := saved.GetResource("books").ReadAll()
+		is.NoErr(err)
+		is.Equal(contents[0], []string{"author", "title", "year"})
+	})
+	t.Run("InvalidDescriptor", func(t *testing.T) {
+		is := is.New(t)
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		is.NoErr(ioutil.WriteFile(filepath.Join(dir, "local.csv"), []byte("name\nold"), 0666))
+		// The profile requires resource paths, so inlined files are invalid.
+		profile := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"properties": {"resources": {"items": {"required": ["path"]}}}}`)
+		}))
+		defer profile.Close()
+		pkg, err := FromString(fmt.Sprintf(`{"profile": %q, "resources": [
+			{"name": "local", "path": "local.csv"},
+			{"name": "remote", "path": "%s/remote.csv"}
+		]}`, profile.URL, ts.URL), src, validator.InMemoryLoader())
+		is.NoErr(err)
+		if err := pkg.SaveTo(dir, InlineFiles(1024)); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		// The directory is not changed.
+		is.Equal(readFile(t, filepath.Join(dir, "local.csv")), "name\nold")
+		files, err := ioutil.ReadDir(dir)
+		is.NoErr(err)
+		is.Equal(len(files), 1)
+	})
+	t.Run("InvalidResource", func(t *testing.T) {
+		dir := tempDir(t)
+		defer os.RemoveAll(dir)
+		pkg, err := FromString(`{"resources": [{"name": "res", "path": "foo.csv"}]}`, src, validator.InMemoryLoader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pkg.SaveTo(dir); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "datapackage.json")); !os.IsNotExist(err) {
+			t.Fatalf("want:not exist got:%v", err)
+		}
+	})
+}
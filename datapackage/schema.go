@@ -1,45 +1,86 @@
 package datapackage
 
 import (
-	"bytes"
 	"encoding/json"
-	"io"
-	"io/ioutil"
-	"net/http"
-	"os"
-	"strings"
+	"fmt"
 
 	"github.com/frictionlessdata/tableschema-go/schema"
 )
 
 func loadSchema(p string) (map[string]interface{}, error) {
-	var reader io.Reader
-	if strings.HasPrefix(p, "http") {
-		resp, err := http.Get(p)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-		reader = resp.Body
-	} else {
-		f, err := os.Open(p)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		reader = f
-	}
-	buf, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-	_, err = schema.Read(bytes.NewBuffer(buf))
+	ret, err := loadExternal(p)
 	if err != nil {
 		return nil, err
 	}
-	var ret map[string]interface{}
-	if err := json.Unmarshal(buf, &ret); err != nil {
+	if _, err := parseSchema(ret); err != nil {
 		return nil, err
 	}
 	return ret, nil
 }
+
+const missingValuesProp = "missingValues"
+
+// parseSchema parses the table schema descriptor. Missing values are propagated to
+// the fields, which might override them (Table Schema v2). Labeled missing
+// values (Table Schema v2) are also supported.
+func parseSchema(d interface{}) (schema.Schema, error) {
+	m, ok := d.(map[string]interface{})
+	if !ok {
+		return schema.Schema{}, fmt.Errorf("schema must be a JSON object. got:%v", d)
+	}
+	cpy := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		cpy[k] = v
+	}
+	schMissing, hasSchMissing := missingValues(m[missingValuesProp])
+	if hasSchMissing {
+		cpy[missingValuesProp] = schMissing
+	}
+	buf, err := json.Marshal(cpy)
+	if err != nil {
+		return schema.Schema{}, err
+	}
+	var s schema.Schema
+	if err := json.Unmarshal(buf, &s); err != nil {
+		return schema.Schema{}, fmt.Errorf("error unmarshaling schema:%w", err)
+	}
+	fields, _ := m["fields"].([]interface{})
+	for i := range s.Fields {
+		mv, ok := schMissing, hasSchMissing
+		if i < len(fields) {
+			if fMap, isMap := fields[i].(map[string]interface{}); isMap {
+				if fMissing, hasFMissing := missingValues(fMap[missingValuesProp]); hasFMissing {
+					mv, ok = fMissing, true
+				}
+			}
+		}
+		if ok {
+			s.Fields[i].MissingValues = make(map[string]struct{}, len(mv))
+			for _, v := range mv {
+				s.Fields[i].MissingValues[v] = struct{}{}
+			}
+		}
+	}
+	return s, nil
+}
+
+// missingValues returns the list of missing values. The list could be made of strings or
+// objects containing the value and label properties.
+func missingValues(i interface{}) ([]string, bool) {
+	l, ok := i.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	ret := make([]string, 0, len(l))
+	for _, v := range l {
+		switch v := v.(type) {
+		case string:
+			ret = append(ret, v)
+		case map[string]interface{}:
+			if vStr, ok := v["value"].(string); ok {
+				ret = append(ret, vStr)
+			}
+		}
+	}
+	return ret, true
+}
@@ -0,0 +1,177 @@
+package datapackage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionBump is a semantic version increment.
+type VersionBump int
+
+// Version increments, from the smallest to the largest.
+const (
+	BumpNone VersionBump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b VersionBump) String() string {
+	switch b {
+	case BumpNone:
+		return "none"
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+	return fmt.Sprintf("VersionBump(%d)", int(b))
+}
+
+// SemVer is a semantic version, as defined by https://semver.org.
+type SemVer struct {
+	Major, Minor, Patch uint64
+	PreRelease          string
+	Build               string
+}
+
+var semVerRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// ParseVersion parses the semantic version (e.g. "1.2.3-rc.1+build.5").
+func ParseVersion(s string) (SemVer, error) {
+	m := semVerRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return SemVer{}, fmt.Errorf("invalid semantic version:%q", s)
+	}
+	var v SemVer
+	for i, dst := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		n, err := strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return SemVer{}, fmt.Errorf("invalid semantic version:%q: %w", s, err)
+		}
+		*dst = n
+	}
+	v.PreRelease, v.Build = m[4], m[5]
+	return v, nil
+}
+
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		s += "-" + v.PreRelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Bump returns the version incremented by the passed-in bump. Lower components are reset and
+// pre-release and build metadata are dropped. Pre-releases which already precede the bumped
+// version are released instead (e.g. a patch bump of 1.0.0-rc.1 returns 1.0.0).
+func (v SemVer) Bump(b VersionBump) SemVer {
+	pre := v.PreRelease != ""
+	switch b {
+	case BumpNone:
+		return v
+	case BumpMajor:
+		if pre && v.Minor == 0 && v.Patch == 0 {
+			return SemVer{Major: v.Major}
+		}
+		return SemVer{Major: v.Major + 1}
+	case BumpMinor:
+		if pre && v.Patch == 0 {
+			return SemVer{Major: v.Major, Minor: v.Minor}
+		}
+		return SemVer{Major: v.Major, Minor: v.Minor + 1}
+	}
+	if pre {
+		return SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	}
+	return SemVer{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// Compare returns -1, 0 or 1 depending on whether the version precedes, is equal or follows the
+// passed-in one. Build metadata is ignored, as required by the specification.
+func (v SemVer) Compare(o SemVer) int {
+	for _, c := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			return cmpUint(c[0], c[1])
+		}
+	}
+	switch {
+	case v.PreRelease == o.PreRelease:
+		return 0
+	case v.PreRelease == "":
+		return 1
+	case o.PreRelease == "":
+		return -1
+	}
+	a, b := strings.Split(v.PreRelease, "."), strings.Split(o.PreRelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		na, errA := strconv.ParseUint(a[i], 10, 64)
+		nb, errB := strconv.ParseUint(b[i], 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			return cmpUint(na, nb)
+		case errA == nil: // Numeric identifiers have lower precedence.
+			return -1
+		case errB == nil:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return cmpUint(uint64(len(a)), uint64(len(b)))
+}
+
+func cmpUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Version returns the package version, parsed as a semantic version. An error is returned if
+// the package has no version or it is not a valid semantic version.
+func (p *Package) Version() (SemVer, error) {
+	d, _ := p.snapshot()
+	return descriptorVersion(d)
+}
+
+func descriptorVersion(d map[string]interface{}) (SemVer, error) {
+	s, ok := d[versionPropName].(string)
+	if !ok {
+		return SemVer{}, fmt.Errorf("package has no version")
+	}
+	return ParseVersion(s)
+}
+
+// BumpVersion increments the package version, returning the new version.
+func (p *Package) BumpVersion(b VersionBump) (SemVer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	v, err := descriptorVersion(p.descriptor)
+	if err != nil {
+		return SemVer{}, err
+	}
+	v = v.Bump(b)
+	if err := p.updateProperties(map[string]interface{}{versionPropName: v.String()}); err != nil {
+		return SemVer{}, err
+	}
+	return v, nil
+}
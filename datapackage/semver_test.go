@@ -0,0 +1,99 @@
+package datapackage
+
+import (
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestParseVersion(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		v, err := ParseVersion("1.2.3-rc.1+build.5")
+		is.NoErr(err)
+		is.Equal(v, SemVer{Major: 1, Minor: 2, Patch: 3, PreRelease: "rc.1", Build: "build.5"})
+		is.Equal(v.String(), "1.2.3-rc.1+build.5")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range []string{"", "1.2", "v1.2.3", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "99999999999999999999.0.0"} {
+			if _, err := ParseVersion(s); err == nil {
+				t.Fatalf("%q want:err got:nil", s)
+			}
+		}
+	})
+}
+
+func TestSemVer_Compare(t *testing.T) {
+	is := is.New(t)
+	// Precedence example from the specification.
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, err := ParseVersion(ordered[i])
+		is.NoErr(err)
+		b, err := ParseVersion(ordered[i+1])
+		is.NoErr(err)
+		is.Equal(a.Compare(b), -1)
+		is.Equal(b.Compare(a), 1)
+		is.Equal(a.Compare(a), 0)
+	}
+	is.Equal(SemVer{Major: 1, Build: "a"}.Compare(SemVer{Major: 1, Build: "b"}), 0)
+}
+
+func TestSemVer_Bump(t *testing.T) {
+	is := is.New(t)
+	v := SemVer{Major: 1, Minor: 2, Patch: 3, Build: "5"}
+	is.Equal(v.Bump(BumpNone), v)
+	is.Equal(v.Bump(BumpPatch).String(), "1.2.4")
+	is.Equal(v.Bump(BumpMinor).String(), "1.3.0")
+	is.Equal(v.Bump(BumpMajor).String(), "2.0.0")
+
+	// Pre-releases.
+	data := []struct {
+		v    string
+		b    VersionBump
+		want string
+	}{
+		{"1.0.0-rc.1", BumpPatch, "1.0.0"},
+		{"1.0.0-rc.1", BumpMinor, "1.0.0"},
+		{"1.0.0-rc.1", BumpMajor, "1.0.0"},
+		{"1.2.3-rc.1+5", BumpPatch, "1.2.3"},
+		{"1.2.3-rc.1", BumpMinor, "1.3.0"},
+		{"1.2.0-rc.1", BumpMinor, "1.2.0"},
+		{"1.2.0-rc.1", BumpMajor, "2.0.0"},
+	}
+	for _, d := range data {
+		v, err := ParseVersion(d.v)
+		is.NoErr(err)
+		is.Equal(v.Bump(d.b).String(), d.want)
+	}
+}
+
+func TestPackage_Version(t *testing.T) {
+	t.Run("Bump", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"version": "1.2.3", "resources": [{"name": "res1", "path": "foo.csv"}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		v, err := pkg.Version()
+		is.NoErr(err)
+		is.Equal(v, SemVer{Major: 1, Minor: 2, Patch: 3})
+		v, err = pkg.BumpVersion(BumpMinor)
+		is.NoErr(err)
+		is.Equal(v.String(), "1.3.0")
+		is.Equal(pkg.Descriptor()["version"], "1.3.0")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, in := range []string{`{"resources": [{"name": "res1", "path": "foo.csv"}]}`, `{"version": "1.0", "resources": [{"name": "res1", "path": "foo.csv"}]}`} {
+			pkg, err := FromString(in, ".", validator.InMemoryLoader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := pkg.Version(); err == nil {
+				t.Fatalf("want:err got:nil")
+			}
+			if _, err := pkg.BumpVersion(BumpPatch); err == nil {
+				t.Fatalf("want:err got:nil")
+			}
+		}
+	})
+}
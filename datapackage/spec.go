@@ -0,0 +1,122 @@
+package datapackage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Versions of the Data Package specification.
+const (
+	// SpecV1 is the version 1 of the specification (https://specs.frictionlessdata.io/).
+	SpecV1 = "1.0"
+	// SpecV2 is the version 2 of the specification (https://datapackage.org/).
+	SpecV2 = "2.0"
+)
+
+const (
+	schemaURLProp         = "$schema"
+	typeProp              = "type"
+	tableResourceType     = "table"
+	v1ProfilesURLPrefix   = "https://datapackage.org/profiles/1.0/"
+	v2ProfilesURLPrefix   = "https://datapackage.org/profiles/2.0/"
+	dataPackageProfileV2  = "data-package-v2"
+	dataResourceProfileV2 = "data-resource-v2"
+)
+
+// Official profile URLs and their respective registry ids.
+var profileURLs = map[string]string{
+	v1ProfilesURLPrefix + "datapackage.json":  defaultDataPackageProfile,
+	v1ProfilesURLPrefix + "dataresource.json": defaultResourceProfile,
+	v2ProfilesURLPrefix + "datapackage.json":  dataPackageProfileV2,
+	v2ProfilesURLPrefix + "dataresource.json": dataResourceProfileV2,
+}
+
+// specVersion returns the version of the specification the descriptor targets. Descriptors
+// without the $schema property (or pointing to v1 profiles) target v1. As custom profiles
+// must extend the official ones, any other $schema targets v2.
+func specVersion(d map[string]interface{}) string {
+	s, ok := d[schemaURLProp]
+	if !ok {
+		return SpecV1
+	}
+	if sStr, ok := s.(string); ok && strings.HasPrefix(sStr, v1ProfilesURLPrefix) {
+		return SpecV1
+	}
+	return SpecV2
+}
+
+// resourceSpecVersion returns the version of the specification the resource descriptor targets.
+// Resources without the $schema property inherit the version of the package.
+func resourceSpecVersion(d map[string]interface{}, pkgVersion string) string {
+	if _, ok := d[schemaURLProp]; ok {
+		return specVersion(d)
+	}
+	return pkgVersion
+}
+
+// profileProperty returns the descriptor property which holds the profile.
+func profileProperty(version string) string {
+	if version == SpecV2 {
+		return schemaURLProp
+	}
+	return profilePropName
+}
+
+// descriptorProfile returns the profile the descriptor must be validated against. Official
+// profile URLs are mapped to their registry ids. The passed-in default is returned
+// if the descriptor does not declare a profile.
+func descriptorProfile(d map[string]interface{}, version, defaultProfile string) (string, bool) {
+	prop := profileProperty(version)
+	if d[prop] == nil {
+		return defaultProfile, true
+	}
+	profile, ok := d[prop].(string)
+	if !ok {
+		return "", false
+	}
+	if id, ok := profileURLs[profile]; ok {
+		profile = id
+	}
+	return profile, true
+}
+
+const (
+	headerRowsProp  = "headerRows"
+	commentCharProp = "commentChar"
+	commentRowsProp = "commentRows"
+	fieldsMatchProp = "fieldsMatch"
+	categoriesProp  = "categories"
+)
+
+// unsupportedTableProperty returns the v2 dialect or schema property of the resource descriptor
+// which can not be applied when reading or writing its data, or an empty string if there is none.
+// Properties holding their default values are supported, as they do not change the data access.
+func unsupportedTableProperty(d map[string]interface{}) string {
+	if dialect, ok := d[dialectProp].(map[string]interface{}); ok {
+		if hr, ok := dialect[headerRowsProp]; ok {
+			rows, _ := hr.([]interface{})
+			if len(rows) != 1 || fmt.Sprint(rows[0]) != "1" {
+				return dialectProp + "." + headerRowsProp
+			}
+		}
+		for _, p := range []string{commentCharProp, commentRowsProp} {
+			if _, ok := dialect[p]; ok {
+				return dialectProp + "." + p
+			}
+		}
+	}
+	sch, ok := d[schemaProp].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if fm, ok := sch[fieldsMatchProp]; ok && fm != "exact" {
+		return schemaProp + "." + fieldsMatchProp
+	}
+	fields, _ := sch["fields"].([]interface{})
+	for _, f := range fields {
+		if fMap, ok := f.(map[string]interface{}); ok && fMap[categoriesProp] != nil {
+			return schemaProp + ".fields." + categoriesProp
+		}
+	}
+	return ""
+}
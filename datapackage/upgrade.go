@@ -0,0 +1,334 @@
+package datapackage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/clone"
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"gopkg.in/yaml.v3"
+)
+
+// UpgradeNote describes a change performed while upgrading a descriptor from v1 to v2.
+type UpgradeNote struct {
+	// Path is the JSON Pointer (RFC 6901) of the changed property, e.g. "/resources/0/url".
+	Path string
+	// Message describes the change.
+	Message string
+	// Lossy indicates that information was dropped while upgrading.
+	Lossy bool
+}
+
+func (n UpgradeNote) String() string {
+	if n.Lossy {
+		return fmt.Sprintf("%s: %s (lossy)", n.Path, n.Message)
+	}
+	return fmt.Sprintf("%s: %s", n.Path, n.Message)
+}
+
+const (
+	licensePropName      = "license"
+	licensesPropName     = "licenses"
+	contributorsPropName = "contributors"
+	sourcesPropName      = "sources"
+	urlProp              = "url"
+	primaryKeyProp       = "primaryKey"
+	foreignKeysProp      = "foreignKeys"
+	fieldsProp           = "fields"
+	referenceProp        = "reference"
+	v2PackageSchemaURL   = v2ProfilesURLPrefix + "datapackage.json"
+)
+
+type upgrader struct {
+	notes []UpgradeNote
+}
+
+func (u *upgrader) note(path string, lossy bool, format string, a ...interface{}) {
+	u.notes = append(u.notes, UpgradeNote{Path: path, Message: fmt.Sprintf(format, a...), Lossy: lossy})
+}
+
+// Normalize converts a v1 package descriptor into its v2 equivalent. The passed-in
+// descriptor is not modified. The returned notes describe every change performed,
+// flagging the ones that dropped information. External schema and dialect references
+// are not followed: they are flagged as lossy, as their contents are not upgraded.
+// Descriptors already targeting v2 are returned unchanged.
+func Normalize(descriptor map[string]interface{}) (map[string]interface{}, []UpgradeNote, error) {
+	cpy, err := clone.Descriptor(descriptor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if specVersion(cpy) == SpecV2 {
+		return cpy, nil, nil
+	}
+	u := &upgrader{}
+	u.upgradePackage(cpy)
+	return cpy, u.notes, nil
+}
+
+// Upgrade converts the package descriptor to v2 (see Normalize). The package is only
+// updated if the upgraded descriptor is valid, otherwise the error will be returned.
+// Default values filled in by New are not upgraded, so notes only refer to the
+// properties of the original descriptor.
+func (p *Package) Upgrade(loaders ...validator.RegistryLoader) ([]UpgradeNote, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	d, notes, err := Normalize(p.savedDescriptor(saveOptions{inline: true}))
+	if err != nil {
+		return nil, err
+	}
+	if err := p.updateWith(d, loaders...); err != nil {
+		return nil, fmt.Errorf("upgraded descriptor is invalid: %w", err)
+	}
+	return notes, nil
+}
+
+// LoadUpgraded loads the JSON or YAML (picked by the path extension) descriptor from the
+// specified URL or file path and upgrades it to v2 (see Normalize). Unlike Load followed by
+// Package.Upgrade, only the upgraded descriptor is validated, so legacy v1 descriptors can
+// be loaded. The returned package keeps the layout of the original descriptor: saving it
+// keeps the property order and indentation (see SaveDescriptor). External schemas and
+// dialects are loaded as they are, and saved back as references: their files are not
+// upgraded (see Normalize).
+func LoadUpgraded(path string, loaders ...validator.RegistryLoader) (*Package, []UpgradeNote, error) {
+	if strings.HasSuffix(path, ".zip") {
+		return nil, nil, fmt.Errorf("zip files can not be upgraded: %s", path)
+	}
+	_, contents, err := read(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading path contents (%s): %w", path, err)
+	}
+	var d map[string]interface{}
+	var layout *descriptorLayout
+	if isYAML(path) {
+		var n yaml.Node
+		if err := yaml.Unmarshal(contents, &n); err != nil {
+			return nil, nil, err
+		}
+		d, err = yamlDescriptor(&n)
+		layout = &descriptorLayout{order: yamlKeyOrder(&n)}
+	} else {
+		d, err = decodeDescriptor(bytes.NewReader(contents))
+		layout = jsonLayout(contents)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing descriptor: %w", err)
+	}
+	upgraded, notes, err := Normalize(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, err := New(upgraded, getBasepath(path), loaders...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("upgraded descriptor is invalid: %w", err)
+	}
+	pkg.layout = layout
+	return pkg, notes, nil
+}
+
+func (u *upgrader) upgradePackage(d map[string]interface{}) {
+	// Profile.
+	profile, _ := d[profilePropName].(string)
+	delete(d, profilePropName)
+	d[schemaURLProp] = v2PackageSchemaURL
+	switch {
+	case profile == "":
+		u.note("/$schema", false, "set to %s", v2PackageSchemaURL)
+	case profile == defaultDataPackageProfile:
+		u.note("/profile", false, "replaced by $schema")
+	case profile == tabularDataPackageProfileName:
+		u.note("/profile", false, "%s replaced by $schema, resources are marked as tables", profile)
+	case isCustomProfile(profile):
+		d[schemaURLProp] = profile
+		u.note("/profile", false, "custom profile moved to $schema, it must extend the v2 profile")
+	default:
+		u.note("/profile", true, "profile %s has no v2 equivalent, replaced by $schema", profile)
+	}
+	u.upgradeLicenses(d, "")
+	if contributors, ok := d[contributorsPropName].([]interface{}); ok {
+		for i, c := range contributors {
+			if cMap, ok := c.(map[string]interface{}); ok {
+				u.upgradeContributor(cMap, fmt.Sprintf("/%s/%d", contributorsPropName, i))
+			}
+		}
+	}
+	u.upgradeSources(d, "")
+	resources, _ := d[resourcePropName].([]interface{})
+	for i, r := range resources {
+		if rMap, ok := r.(map[string]interface{}); ok {
+			u.upgradeResource(rMap, fmt.Sprintf("/%s/%d", resourcePropName, i), profile == tabularDataPackageProfileName)
+		}
+	}
+}
+
+func (u *upgrader) upgradeResource(r map[string]interface{}, path string, tabular bool) {
+	// Profile.
+	if profile, ok := r[profilePropName].(string); ok {
+		delete(r, profilePropName)
+		switch {
+		case profile == defaultResourceProfile:
+			u.note(path+"/profile", false, "removed")
+		case profile == tabularDataResourceProfile:
+			r[typeProp] = tableResourceType
+			u.note(path+"/profile", false, "%s replaced by type %s", profile, tableResourceType)
+		case isCustomProfile(profile):
+			r[schemaURLProp] = profile
+			u.note(path+"/profile", false, "custom profile moved to $schema, it must extend the v2 profile")
+		default:
+			u.note(path+"/profile", true, "profile %s has no v2 equivalent, removed", profile)
+		}
+	}
+	if tabular && r[typeProp] == nil {
+		r[typeProp] = tableResourceType
+		u.note(path+"/type", false, "set to %s", tableResourceType)
+	}
+	// Legacy url property.
+	if url, ok := r[urlProp]; ok {
+		delete(r, urlProp)
+		if r[pathProp] == nil && r[dataProp] == nil {
+			r[pathProp] = url
+			u.note(path+"/url", false, "renamed to path")
+		} else {
+			u.note(path+"/url", true, "removed, resource already has path or data")
+		}
+	}
+	u.upgradeLicenses(r, path)
+	u.upgradeSources(r, path)
+	// External schemas and dialects are kept as references, their files are not upgraded.
+	for _, prop := range refProps {
+		if ref, ok := r[prop].(string); ok {
+			u.note(path+"/"+prop, true, "external reference %s not upgraded", ref)
+		}
+	}
+	if dMap, ok := r[dialectProp].(map[string]interface{}); ok {
+		u.upgradeDialect(dMap, path+"/dialect")
+	}
+	if sMap, ok := r[schemaProp].(map[string]interface{}); ok {
+		u.upgradeSchema(sMap, path+"/schema")
+	}
+}
+
+// upgradeLicenses converts the legacy license property into the licenses list and
+// renames legacy license properties.
+func (u *upgrader) upgradeLicenses(d map[string]interface{}, path string) {
+	if l, ok := d[licensePropName]; ok {
+		delete(d, licensePropName)
+		var license map[string]interface{}
+		switch l := l.(type) {
+		case string:
+			license = map[string]interface{}{"name": l}
+		case map[string]interface{}:
+			license = l
+		}
+		switch {
+		case license == nil:
+			u.note(path+"/license", true, "invalid license removed")
+		case d[licensesPropName] != nil:
+			u.note(path+"/license", true, "removed, licenses is already declared")
+		default:
+			d[licensesPropName] = []interface{}{license}
+			u.note(path+"/license", false, "moved to licenses")
+		}
+	}
+	licenses, _ := d[licensesPropName].([]interface{})
+	for i, l := range licenses {
+		lPath := fmt.Sprintf("%s/%s/%d", path, licensesPropName, i)
+		switch lVal := l.(type) {
+		case string:
+			licenses[i] = map[string]interface{}{"name": lVal}
+			u.note(lPath, false, "converted to object")
+		case map[string]interface{}:
+			u.rename(lVal, lPath, "type", "name")
+			u.rename(lVal, lPath, urlProp, pathProp)
+		}
+	}
+}
+
+func (u *upgrader) upgradeContributor(c map[string]interface{}, path string) {
+	if role, ok := c["role"]; ok {
+		delete(c, "role")
+		if rStr, ok := role.(string); ok && c["roles"] == nil {
+			c["roles"] = []interface{}{rStr}
+			u.note(path+"/role", false, "converted to roles")
+		} else {
+			u.note(path+"/role", true, "removed")
+		}
+	}
+	u.rename(c, path, "web", pathProp)
+	u.rename(c, path, "organisation", "organization")
+}
+
+func (u *upgrader) upgradeSources(d map[string]interface{}, path string) {
+	sources, _ := d[sourcesPropName].([]interface{})
+	for i, s := range sources {
+		if sMap, ok := s.(map[string]interface{}); ok {
+			sPath := fmt.Sprintf("%s/%s/%d", path, sourcesPropName, i)
+			u.rename(sMap, sPath, "web", pathProp)
+			u.rename(sMap, sPath, urlProp, pathProp)
+		}
+	}
+}
+
+// CSV Dialect properties which were dropped by the v2 Table Dialect.
+var droppedDialectProps = []string{"caseSensitiveHeader", "csvddfVersion"}
+
+func (u *upgrader) upgradeDialect(d map[string]interface{}, path string) {
+	for _, p := range droppedDialectProps {
+		if _, ok := d[p]; ok {
+			delete(d, p)
+			u.note(path+"/"+p, true, "removed, not part of the Table Dialect")
+		}
+	}
+}
+
+func (u *upgrader) upgradeSchema(s map[string]interface{}, path string) {
+	u.stringToList(s, path, primaryKeyProp)
+	fks, _ := s[foreignKeysProp].([]interface{})
+	for i, fk := range fks {
+		fkMap, ok := fk.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fkPath := fmt.Sprintf("%s/%s/%d", path, foreignKeysProp, i)
+		u.stringToList(fkMap, fkPath, fieldsProp)
+		if ref, ok := fkMap[referenceProp].(map[string]interface{}); ok {
+			u.stringToList(ref, fkPath+"/"+referenceProp, fieldsProp)
+			if res, ok := ref["resource"].(string); ok && res == "" {
+				delete(ref, "resource")
+				u.note(fkPath+"/reference/resource", false, "self-reference expressed by omitting resource")
+			}
+		}
+	}
+	fields, _ := s[fieldsProp].([]interface{})
+	for i, f := range fields {
+		fMap, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if format, ok := fMap["format"].(string); ok && strings.HasPrefix(format, "fmt:") {
+			fMap["format"] = strings.TrimPrefix(format, "fmt:")
+			u.note(fmt.Sprintf("%s/%s/%d/format", path, fieldsProp, i), false, "deprecated fmt: prefix removed")
+		}
+	}
+}
+
+func (u *upgrader) stringToList(d map[string]interface{}, path, prop string) {
+	if s, ok := d[prop].(string); ok {
+		d[prop] = []interface{}{s}
+		u.note(path+"/"+prop, false, "converted to list")
+	}
+}
+
+func (u *upgrader) rename(d map[string]interface{}, path, from, to string) {
+	v, ok := d[from]
+	if !ok {
+		return
+	}
+	delete(d, from)
+	if _, exists := d[to]; exists {
+		u.note(path+"/"+from, true, "removed, %s is already declared", to)
+		return
+	}
+	d[to] = v
+	u.note(path+"/"+from, false, "renamed to %s", to)
+}
@@ -0,0 +1,196 @@
+package datapackage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("Package", func(t *testing.T) {
+		is := is.New(t)
+		in := map[string]interface{}{
+			"profile": "tabular-data-package",
+			"license": "ODC-PDDL-1.0",
+			"contributors": []interface{}{
+				map[string]interface{}{"title": "Joe", "role": "author", "web": "http://joe.com"},
+			},
+			"sources": []interface{}{map[string]interface{}{"title": "World Bank", "web": "http://wb.org"}},
+			"resources": []interface{}{
+				map[string]interface{}{
+					"name":    "res1",
+					"url":     "http://foo.com/data.csv",
+					"profile": "tabular-data-resource",
+					"dialect": map[string]interface{}{"delimiter": ";", "caseSensitiveHeader": true},
+					"schema": map[string]interface{}{
+						"fields":     []interface{}{map[string]interface{}{"name": "d", "type": "date", "format": "fmt:%Y"}},
+						"primaryKey": "d",
+						"foreignKeys": []interface{}{map[string]interface{}{
+							"fields":    "d",
+							"reference": map[string]interface{}{"resource": "", "fields": "d"},
+						}},
+					},
+				},
+			},
+		}
+		out, notes, err := Normalize(in)
+		is.NoErr(err)
+		is.Equal(out, map[string]interface{}{
+			"$schema":  "https://datapackage.org/profiles/2.0/datapackage.json",
+			"licenses": []interface{}{map[string]interface{}{"name": "ODC-PDDL-1.0"}},
+			"contributors": []interface{}{
+				map[string]interface{}{"title": "Joe", "roles": []interface{}{"author"}, "path": "http://joe.com"},
+			},
+			"sources": []interface{}{map[string]interface{}{"title": "World Bank", "path": "http://wb.org"}},
+			"resources": []interface{}{
+				map[string]interface{}{
+					"name":    "res1",
+					"path":    "http://foo.com/data.csv",
+					"type":    "table",
+					"dialect": map[string]interface{}{"delimiter": ";"},
+					"schema": map[string]interface{}{
+						"fields":     []interface{}{map[string]interface{}{"name": "d", "type": "date", "format": "%Y"}},
+						"primaryKey": []interface{}{"d"},
+						"foreignKeys": []interface{}{map[string]interface{}{
+							"fields":    []interface{}{"d"},
+							"reference": map[string]interface{}{"fields": []interface{}{"d"}},
+						}},
+					},
+				},
+			},
+		})
+		lossy := 0
+		for _, n := range notes {
+			if n.Lossy {
+				lossy++
+				is.Equal(n.Path, "/resources/0/dialect/caseSensitiveHeader")
+			}
+		}
+		is.Equal(lossy, 1)
+		// The input must not be modified.
+		is.Equal(in["profile"], "tabular-data-package")
+	})
+	t.Run("CustomProfile", func(t *testing.T) {
+		is := is.New(t)
+		out, notes, err := Normalize(map[string]interface{}{"profile": "http://example.com/profile.json", "resources": []interface{}{}})
+		is.NoErr(err)
+		is.Equal(out["$schema"], "http://example.com/profile.json")
+		is.Equal(len(notes), 1)
+		is.True(!notes[0].Lossy)
+	})
+	t.Run("UnknownProfile", func(t *testing.T) {
+		is := is.New(t)
+		_, notes, err := Normalize(map[string]interface{}{"profile": "fiscal-data-package", "resources": []interface{}{}})
+		is.NoErr(err)
+		is.Equal(len(notes), 1)
+		is.True(notes[0].Lossy)
+	})
+	t.Run("AlreadyV2", func(t *testing.T) {
+		is := is.New(t)
+		in := map[string]interface{}{"$schema": "https://datapackage.org/profiles/2.0/datapackage.json", "url": "foo"}
+		out, notes, err := Normalize(in)
+		is.NoErr(err)
+		is.Equal(out, in)
+		is.Equal(len(notes), 0)
+	})
+}
+
+func TestPackage_Upgrade(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		notes, err := pkg.Upgrade(validator.InMemoryLoader())
+		is.NoErr(err)
+		// Notes refer to the original descriptor, not to the default profiles filled by New.
+		is.Equal(notes, []UpgradeNote{{Path: "/$schema", Message: "set to https://datapackage.org/profiles/2.0/datapackage.json"}})
+		is.Equal(pkg.SpecVersion(), SpecV2)
+		is.Equal(pkg.GetResource("res1").SpecVersion(), SpecV2)
+		is.Equal(pkg.Descriptor(), map[string]interface{}{
+			"$schema":   "https://datapackage.org/profiles/2.0/datapackage.json",
+			"resources": []interface{}{map[string]interface{}{"name": "res1", "path": "foo.csv"}},
+		})
+	})
+	t.Run("DeclaredProfile", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := FromString(`{"profile": "data-package", "resources": [{"name": "res1", "path": "foo.csv", "profile": "tabular-data-resource", "schema": {"fields": [{"name": "a"}]}}]}`, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		notes, err := pkg.Upgrade(validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(len(notes), 2)
+		is.Equal(notes[0].Path, "/profile")
+		is.Equal(notes[1].Path, "/resources/0/profile")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		is := is.New(t)
+		pkg, err := New(map[string]interface{}{"resources": []interface{}{r1}, "contributors": []interface{}{map[string]interface{}{"title": "joe", "roles": []interface{}{}}}}, ".", validator.InMemoryLoader())
+		is.NoErr(err)
+		_, err = pkg.Upgrade(validator.InMemoryLoader())
+		is.True(err != nil) // roles must not be empty in v2.
+		is.Equal(pkg.SpecVersion(), SpecV1)
+	})
+}
+
+func TestLoadUpgraded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datapackage_upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	t.Run("JSON", func(t *testing.T) {
+		is := is.New(t)
+		fName := filepath.Join(dir, "datapackage.json")
+		// Resources using url are not valid v1 resources.
+		is.NoErr(ioutil.WriteFile(fName, []byte("{\n\t\"resources\": [{\"url\": \"a.csv\", \"name\": \"a\"}],\n\t\"name\": \"pkg\"\n}\n"), 0600))
+		if _, err := Load(fName, validator.InMemoryLoader()); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		pkg, notes, err := LoadUpgraded(fName, validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(len(notes), 2) // $schema and url.
+		is.Equal(pkg.SpecVersion(), SpecV2)
+		is.NoErr(pkg.SaveDescriptor(fName))
+		buf, err := ioutil.ReadFile(fName)
+		is.NoErr(err)
+		is.Equal(string(buf), "{\n\t\"resources\": [\n\t\t{\n\t\t\t\"name\": \"a\",\n\t\t\t\"path\": \"a.csv\"\n\t\t}\n\t],\n\t\"name\": \"pkg\",\n\t\"$schema\": \"https://datapackage.org/profiles/2.0/datapackage.json\"\n}\n")
+	})
+	t.Run("YAML", func(t *testing.T) {
+		is := is.New(t)
+		fName := filepath.Join(dir, "datapackage.yaml")
+		is.NoErr(ioutil.WriteFile(fName, []byte("resources:\n  - name: a\n    url: a.csv\n"), 0600))
+		pkg, _, err := LoadUpgraded(fName, validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(pkg.GetResource("a").Descriptor()["path"], "a.csv")
+	})
+	t.Run("ExternalReferences", func(t *testing.T) {
+		is := is.New(t)
+		is.NoErr(ioutil.WriteFile(filepath.Join(dir, "schema.json"), []byte(`{"fields": [{"name": "a", "type": "string"}], "primaryKey": "a"}`), 0600))
+		fName := filepath.Join(dir, "refs.json")
+		is.NoErr(ioutil.WriteFile(fName, []byte(`{"resources": [{"name": "a", "path": "a.csv", "schema": "schema.json"}]}`), 0600))
+		pkg, notes, err := LoadUpgraded(fName, validator.InMemoryLoader())
+		is.NoErr(err)
+		is.Equal(len(notes), 2) // $schema and the schema reference.
+		is.Equal(notes[1], UpgradeNote{Path: "/resources/0/schema", Message: "external reference schema.json not upgraded", Lossy: true})
+		is.NoErr(pkg.SaveDescriptor(fName))
+		buf, err := ioutil.ReadFile(fName)
+		is.NoErr(err)
+		is.True(strings.Contains(string(buf), `"schema":"schema.json"`))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		fName := filepath.Join(dir, "invalid.json")
+		if err := ioutil.WriteFile(fName, []byte(`{"resources": [{"name": "a"}]}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := LoadUpgraded(fName, validator.InMemoryLoader()); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if _, _, err := LoadUpgraded(filepath.Join(dir, "foo.zip"), validator.InMemoryLoader()); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
@@ -0,0 +1,383 @@
+package datapackage
+
+import (
+	stdcsv "encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/frictionlessdata/tableschema-go/schema"
+)
+
+const tableHeaderTag = "tableheader"
+
+// RowWriter writes rows to the file of a tabular resource.
+type RowWriter struct {
+	f           *os.File
+	w           *stdcsv.Writer
+	sch         *schema.Schema // Nil if the resource has no schema.
+	missing     string         // Value written for nil values.
+	doubleQuote bool           // Whether quotes within values can be written, doubled.
+	rows        int
+}
+
+// NewWriter creates (or truncates) the resource file and returns a writer to fill it. The file
+// is created relative to the resource base path (the package base path, for package resources)
+// and is written using the resource CSV dialect. Unless the dialect sets header to false, the
+// schema field names are written as header row, as the header property defaults to true (this
+// includes resources without dialect). Dialects disabling doubleQuote can only be used to write
+// values without quotes, as escapeChar is not supported.
+//
+// Only tabular, UTF-8 encoded resources with a single local path can be written. As when reading,
+// the v2 properties listed by GetTable are not supported. The writer must be closed once all rows
+// are written.
+func (r *Resource) NewWriter() (*RowWriter, error) {
+	p, err := r.localPath()
+	if err != nil {
+		return nil, err
+	}
+	if !r.Tabular() {
+		return nil, fmt.Errorf("resource %s: only tabular resources can be written", r.name)
+	}
+	if enc, ok := r.descriptor[encodingProp].(string); ok && !isUTF8(enc) {
+		return nil, fmt.Errorf("resource %s: unsupported encoding:%s", r.name, enc)
+	}
+	if err := r.checkTableProperties(); err != nil {
+		return nil, err
+	}
+	var sch *schema.Schema
+	if r.descriptor[schemaProp] != nil {
+		s, err := r.GetSchema()
+		if err != nil {
+			return nil, err
+		}
+		sch = &s
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return nil, err
+	}
+	dialect := parseDialect(r.descriptor[dialectProp])
+	w := RowWriter{f: f, w: stdcsv.NewWriter(f), sch: sch, doubleQuote: dialect.DoubleQuote}
+	w.w.Comma = dialect.Delimiter
+	if sch != nil && len(sch.MissingValues) > 0 {
+		w.missing = sch.MissingValues[0]
+	}
+	if sch != nil && dialect.Header {
+		header := make([]string, len(sch.Fields))
+		for i, field := range sch.Fields {
+			header[i] = field.Name
+		}
+		if err := w.w.Write(header); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &w, nil
+}
+
+// Write writes all rows to the resource file, replacing its contents. The rows argument must
+// be a slice, whose elements are written as described by RowWriter.Write.
+func (r *Resource) Write(rows interface{}) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("rows must be a slice. got:%T", rows)
+	}
+	w, err := r.NewWriter()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := w.Write(v.Index(i).Interface()); err != nil {
+			w.Close()
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// localPath returns the path of the resource file, if the resource can be written.
+func (r *Resource) localPath() (string, error) {
+	if r.data != nil {
+		return "", fmt.Errorf("resource %s: inline resources can not be written", r.name)
+	}
+	if len(r.path) != 1 {
+		return "", fmt.Errorf("resource %s: only resources with a single path can be written", r.name)
+	}
+	if t, err := checkPath(r.path[0]); err != nil || t != relativePath {
+		return "", fmt.Errorf("resource %s: only resources with local paths can be written", r.name)
+	}
+	if _, remote := parseRemotePath(r.basePath); remote {
+		return "", fmt.Errorf("resource %s: resources of remote packages can not be written", r.name)
+	}
+	return joinPaths(r.basePath, r.path[0]), nil
+}
+
+func isUTF8(enc string) bool {
+	return strings.EqualFold(strings.Replace(enc, "-", "", -1), "utf8")
+}
+
+// Write writes a single row. The row can be:
+//   - []string, which is written as is.
+//   - Row or []interface{}, holding the values in the schema order.
+//   - map[string]interface{}, holding the values keyed by field name.
+//   - a struct (or pointer to struct), whose fields are matched to the schema fields by their
+//     tableheader tag or name, the same way Resource.Cast does.
+//
+// Values are formatted according to their schema fields: booleans use the field trueValues and
+// falseValues, numbers the field decimalChar, and dates and times the field format. Strings are
+// written as is, as long as they are valid values of their fields. Nil values are written as the
+// first schema missing value (an empty string, by default). Rows which are not []string can only
+// be written to resources with a schema.
+func (w *RowWriter) Write(row interface{}) error {
+	record, err := w.record(row)
+	if err != nil {
+		return err
+	}
+	if err := w.checkQuotes(record); err != nil {
+		return err
+	}
+	if err := w.w.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	return nil
+}
+
+func (w *RowWriter) record(row interface{}) ([]string, error) {
+	if record, ok := row.([]string); ok {
+		if w.sch != nil && len(record) != len(w.sch.Fields) {
+			return nil, &RowError{Row: w.rows, Err: fmt.Errorf("the row with %d values does not match the %d fields in the schema", len(record), len(w.sch.Fields))}
+		}
+		return record, nil
+	}
+	if w.sch == nil {
+		return nil, &RowError{Row: w.rows, Err: fmt.Errorf("only []string rows can be written to resources without schema. got:%T", row)}
+	}
+	values := make([]interface{}, len(w.sch.Fields))
+	switch r := row.(type) {
+	case Row:
+		if len(r.values) != len(values) {
+			return nil, &RowError{Row: w.rows, Err: fmt.Errorf("the row with %d values does not match the %d fields in the schema", len(r.values), len(values))}
+		}
+		copy(values, r.values)
+	case []interface{}:
+		if len(r) != len(values) {
+			return nil, &RowError{Row: w.rows, Err: fmt.Errorf("the row with %d values does not match the %d fields in the schema", len(r), len(values))}
+		}
+		copy(values, r)
+	case map[string]interface{}:
+		for i, f := range w.sch.Fields {
+			values[i] = r[f.Name]
+		}
+	default:
+		v := reflect.ValueOf(row)
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, &RowError{Row: w.rows, Err: fmt.Errorf("unsupported row type:%T", row)}
+		}
+		w.structValues(v, values)
+	}
+	record := make([]string, len(values))
+	for i := range values {
+		f := &w.sch.Fields[i]
+		s, err := w.formatValue(f, values[i])
+		if err != nil {
+			return nil, &RowError{Row: w.rows, Field: f.Name, Value: fmt.Sprint(values[i]), Err: err}
+		}
+		record[i] = s
+	}
+	return record, nil
+}
+
+// checkQuotes returns an error if the record holds quotes which can not be written, because
+// the dialect disables doubleQuote.
+func (w *RowWriter) checkQuotes(record []string) error {
+	if w.doubleQuote {
+		return nil
+	}
+	for i, v := range record {
+		if !strings.Contains(v, `"`) {
+			continue
+		}
+		e := &RowError{Row: w.rows, Value: v, Err: fmt.Errorf("values with quotes can not be written when doubleQuote is false")}
+		if w.sch != nil {
+			e.Field = w.sch.Fields[i].Name
+		}
+		return e
+	}
+	return nil
+}
+
+// structValues fills values with the struct fields matching schema fields. Fields of embedded
+// and nested structs are matched too.
+func (w *RowWriter) structValues(v reflect.Value, values []interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" { // Unexported.
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Time{}) {
+			w.structValues(fv, values)
+			continue
+		}
+		name, ok := sf.Tag.Lookup(tableHeaderTag)
+		if !ok {
+			name = sf.Name
+		}
+		if _, pos := w.sch.GetField(name); pos != schema.InvalidPosition {
+			values[pos] = fv.Interface()
+		}
+	}
+}
+
+// Go layouts matching strftime directives, longest directives first.
+var strftimeReplacer = strings.NewReplacer(
+	"%-d", "2", "%_m", " 1", "%-m", "1", "%:z", "Z07:00",
+	"%d", "02", "%B", "January", "%b", "Jan", "%h", "Jan", "%m", "01", "%Y", "2006", "%y", "06",
+	"%H", "15", "%I", "03", "%M", "04", "%S", "05", "%f", "000000", "%z", "Z0700", "%Z", "MST", "%p", "PM",
+)
+
+func timeLayout(format, defaultLayout string) string {
+	switch format {
+	case "", "default", schema.AnyDateFormat:
+		return defaultLayout
+	}
+	return strftimeReplacer.Replace(format)
+}
+
+// formatValue formats the value according to the schema field.
+func (w *RowWriter) formatValue(f *schema.Field, v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return w.missing, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return w.missing, nil
+	}
+	v = rv.Interface()
+	if s, ok := v.(string); ok {
+		if f.Type != schema.StringType && !isFieldMissingValue(w.sch, f, s) {
+			if _, err := f.Cast(s); err != nil {
+				return "", err
+			}
+		}
+		return s, nil
+	}
+	switch f.Type {
+	case schema.StringType:
+		if s, ok := v.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+	case schema.BooleanType:
+		if b, ok := v.(bool); ok {
+			if b {
+				return preferredValue(f.TrueValues, "true"), nil
+			}
+			return preferredValue(f.FalseValues, "false"), nil
+		}
+	case schema.IntegerType:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), nil
+		case reflect.Float32, reflect.Float64:
+			if fl := rv.Float(); fl == math.Trunc(fl) && !math.IsInf(fl, 0) {
+				return strconv.FormatFloat(fl, 'f', -1, 64), nil
+			}
+		}
+		if n, ok := v.(json.Number); ok {
+			return n.String(), nil
+		}
+	case schema.NumberType:
+		var s string
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(rv.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(rv.Uint(), 10)
+		case reflect.Float32:
+			s = strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+		case reflect.Float64:
+			s = strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+		}
+		if n, ok := v.(json.Number); ok {
+			s = n.String()
+		}
+		if s != "" {
+			if f.DecimalChar != "" && f.DecimalChar != "." {
+				s = strings.Replace(s, ".", f.DecimalChar, 1)
+			}
+			return s, nil
+		}
+	case schema.DateType, schema.TimeType, schema.DateTimeType, schema.YearMonthType, schema.YearType:
+		if t, ok := v.(time.Time); ok {
+			switch f.Type {
+			case schema.DateType:
+				return t.Format(timeLayout(f.Format, "2006-01-02")), nil
+			case schema.TimeType:
+				return t.Format(timeLayout(f.Format, "15:04:05")), nil
+			case schema.DateTimeType:
+				return t.Format(timeLayout(f.Format, time.RFC3339)), nil
+			case schema.YearMonthType:
+				return t.Format("2006-01"), nil
+			}
+			return t.Format("2006"), nil
+		}
+		if f.Type == schema.YearType {
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+		}
+	default:
+		return f.Uncast(v)
+	}
+	return "", fmt.Errorf("can not format value of type %T as %s", v, f.Type)
+}
+
+// preferredValue returns the passed-in value if it is one of the allowed values, or the first
+// allowed value otherwise.
+func preferredValue(values []string, v string) string {
+	for _, allowed := range values {
+		if allowed == v {
+			return v
+		}
+	}
+	if len(values) > 0 {
+		return values[0]
+	}
+	return v
+}
+
+// Flush writes any buffered rows to the file.
+func (w *RowWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Close flushes the buffered rows and closes the file.
+func (w *RowWriter) Close() error {
+	err := w.Flush()
+	if closeErr := w.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+package datapackage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/frictionlessdata/tableschema-go/csv"
+	"github.com/matryer/is"
+)
+
+type sale struct {
+	Product string    `tableheader:"product"`
+	Price   float64   `tableheader:"price"`
+	Paid    bool      `tableheader:"paid"`
+	Date    time.Time `tableheader:"date"`
+	Units   *int64    `tableheader:"units"`
+}
+
+func TestResource_Write(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "datapackage_write")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+	pkg, err := FromString(`{"resources": [{
+		"name": "sales",
+		"path": "data/sales.csv",
+		"profile": "tabular-data-resource",
+		"dialect": {"delimiter": ";"},
+		"schema": {
+			"missingValues": ["NA"],
+			"fields": [
+				{"name": "product", "type": "string"},
+				{"name": "price", "type": "number", "decimalChar": ","},
+				{"name": "paid", "type": "boolean", "trueValues": ["Y"], "falseValues": ["N"]},
+				{"name": "date", "type": "date", "format": "%d/%m/%Y"},
+				{"name": "units", "type": "integer"}
+			]
+		}
+	}]}`, dir, validator.InMemoryLoader())
+	is.NoErr(err)
+	res := pkg.GetResource("sales")
+	units := int64(3)
+	date := time.Date(2021, 11, 25, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Structs", func(t *testing.T) {
+		is := is.New(t)
+		sales := []sale{{"foo", 1.5, true, date, &units}, {"bar", 2, false, date, nil}}
+		is.NoErr(res.Write(sales))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "data", "sales.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "product;price;paid;date;units\nfoo;1,5;Y;25/11/2021;3\nbar;2;N;25/11/2021;NA\n")
+
+		// Reading the rows back.
+		rows, err := Rows[sale](res)
+		is.NoErr(err)
+		defer rows.Close()
+		var got []sale
+		for rows.Next() {
+			got = append(got, rows.Row())
+		}
+		is.NoErr(rows.Err())
+		is.Equal(len(got), 2)
+		is.Equal(got[0].Price, 1.5)
+		is.Equal(got[0].Paid, true)
+		is.Equal(got[0].Date, date)
+		is.Equal(*got[0].Units, int64(3))
+		is.Equal(got[1].Paid, false)
+	})
+	t.Run("Writer", func(t *testing.T) {
+		is := is.New(t)
+		w, err := res.NewWriter()
+		is.NoErr(err)
+		is.NoErr(w.Write([]string{"foo", "1", "Y", "01/01/2020", "1"}))
+		is.NoErr(w.Write([]interface{}{"bar", 2.25, nil, date, int64(2)}))
+		is.NoErr(w.Write(map[string]interface{}{"product": "baz", "paid": false}))
+		is.NoErr(w.Write(&sale{Product: "qux", Date: date}))
+		is.NoErr(w.Close())
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "data", "sales.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "product;price;paid;date;units\n"+
+			"foo;1;Y;01/01/2020;1\n"+
+			"bar;2,25;NA;25/11/2021;2\n"+
+			"baz;NA;N;NA;NA\n"+
+			"qux;0;N;25/11/2021;NA\n")
+	})
+	t.Run("Row", func(t *testing.T) {
+		is := is.New(t)
+		is.NoErr(res.Write([]sale{{"foo", 1.5, true, date, &units}}))
+		rows, err := Rows[Row](res)
+		is.NoErr(err)
+		var all []Row
+		for rows.Next() {
+			all = append(all, rows.Row())
+		}
+		is.NoErr(rows.Err())
+		is.NoErr(rows.Close())
+		is.NoErr(res.Write(all))
+		contents, err := res.ReadAll()
+		is.NoErr(err)
+		is.Equal(contents, [][]string{{"foo", "1,5", "Y", "25/11/2021", "3"}})
+	})
+	t.Run("InvalidRows", func(t *testing.T) {
+		is := is.New(t)
+		w, err := res.NewWriter()
+		is.NoErr(err)
+		defer w.Close()
+		if err := w.Write([]string{"foo"}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := w.Write(42); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		err = w.Write(map[string]interface{}{"paid": "maybe", "price": "free", "units": 1.5})
+		rowErr, ok := err.(*RowError)
+		if !ok {
+			t.Fatalf("want:*RowError got:%v", err)
+		}
+		is.Equal(rowErr.Field, "price")
+		is.Equal(rowErr.Value, "free")
+		if err := w.Write(map[string]interface{}{"units": 1.5}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := res.Write(sale{}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("NoSchema", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResource(map[string]interface{}{"name": "res", "path": "res.csv"}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		res.basePath = dir
+		is.NoErr(res.Write([][]string{{"a", "b"}, {"1", "2"}}))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "res.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "a,b\n1,2\n")
+		if err := res.Write([]sale{{}}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("NoDialect", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResource(map[string]interface{}{
+			"name":    "res",
+			"path":    "nodialect.csv",
+			"profile": "tabular-data-resource",
+			"schema": map[string]interface{}{"fields": []interface{}{
+				map[string]interface{}{"name": "product", "type": "string"},
+				map[string]interface{}{"name": "time", "type": "time", "format": "%H:%M:%S.%f"},
+			}},
+		}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		res.basePath = dir
+		tm := time.Date(0, 1, 1, 10, 30, 15, 100000000, time.UTC)
+		is.NoErr(res.Write([]interface{}{[]interface{}{"foo", tm}}))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "nodialect.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "product,time\nfoo,10:30:15.100000\n")
+
+		// Readers of resources without dialect decide whether the file has a header row.
+		var rows []struct {
+			Product string    `tableheader:"product"`
+			Time    time.Time `tableheader:"time"`
+		}
+		is.NoErr(res.Cast(&rows, csv.LoadHeaders()))
+		is.Equal(len(rows), 1)
+		is.Equal(rows[0].Product, "foo")
+		is.Equal(rows[0].Time.Format("15:04:05.000000"), "10:30:15.100000")
+	})
+	t.Run("DoubleQuote", func(t *testing.T) {
+		is := is.New(t)
+		res, err := NewResource(map[string]interface{}{
+			"name":    "res",
+			"path":    "quotes.csv",
+			"dialect": map[string]interface{}{"doubleQuote": false, "header": false},
+		}, validator.MustInMemoryRegistry())
+		is.NoErr(err)
+		res.basePath = dir
+		is.NoErr(res.Write([][]string{{"a", "b,c"}}))
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "quotes.csv"))
+		is.NoErr(err)
+		is.Equal(string(buf), "a,\"b,c\"\n")
+		if err := res.Write([][]string{{"a", `b"c`}}); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("NotWritable", func(t *testing.T) {
+		data := []struct {
+			desc string
+			d    map[string]interface{}
+		}{
+			{"Inline", map[string]interface{}{"name": "res", "data": "a,b", "format": "csv"}},
+			{"Multipart", map[string]interface{}{"name": "res", "path": []interface{}{"a.csv", "b.csv"}}},
+			{"Remote", map[string]interface{}{"name": "res", "path": "http://example.com/a.csv"}},
+			{"NotTabular", map[string]interface{}{"name": "res", "path": "a.json"}},
+			{"Encoding", map[string]interface{}{"name": "res", "path": "a.csv", "encoding": "latin-1"}},
+		}
+		for _, d := range data {
+			d := d
+			t.Run(d.desc, func(t *testing.T) {
+				is := is.New(t)
+				res, err := NewResource(d.d, validator.MustInMemoryRegistry())
+				is.NoErr(err)
+				res.basePath = dir
+				if _, err := res.NewWriter(); err == nil {
+					t.Fatalf("want:err got:nil")
+				}
+			})
+		}
+	})
+}
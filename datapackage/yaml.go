@@ -0,0 +1,240 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"gopkg.in/yaml.v3"
+)
+
+const yamlDescriptorFileNameWithinZip = "datapackage.yaml"
+
+// isYAML checks whether the path points to a YAML file.
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// FromYAMLReader creates a data package from an io.Reader containing a YAML descriptor.
+func FromYAMLReader(r io.Reader, basePath string, loaders ...validator.RegistryLoader) (*Package, error) {
+	var n yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&n); err != nil {
+		return nil, err
+	}
+	descriptor, err := yamlDescriptor(&n)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := New(descriptor, basePath, loaders...)
+	if err != nil {
+		return nil, err
+	}
+	pkg.layout = &descriptorLayout{order: yamlKeyOrder(&n)}
+	return pkg, nil
+}
+
+// FromYAMLString creates a data package from a string representation of the YAML package descriptor.
+func FromYAMLString(in string, basePath string, loaders ...validator.RegistryLoader) (*Package, error) {
+	return FromYAMLReader(strings.NewReader(in), basePath, loaders...)
+}
+
+// WriteYAMLDescriptor writes the YAML data package descriptor to the passed-in writer, the same
+// way SaveDescriptor saves it.
+func (p *Package) WriteYAMLDescriptor(w io.Writer, opts ...SaveOption) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.writeYAML(w, newSaveOptions(opts))
+}
+
+func (p *Package) writeYAML(w io.Writer, opts saveOptions) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(descriptorNode(p.savedDescriptor(opts), p.keyOrder(), opts.canonical)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// MarshalYAML encodes the package descriptor, implementing yaml.Marshaler.
+func (p *Package) MarshalYAML() (interface{}, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return descriptorNode(p.descriptor, p.keyOrder(), false), nil
+}
+
+// UnmarshalYAML decodes and validates the package descriptor, implementing yaml.Unmarshaler.
+// It follows the same rules as UnmarshalJSON.
+func (p *Package) UnmarshalYAML(n *yaml.Node) error {
+	d, err := yamlDescriptor(n)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.valRegistry == nil {
+		return p.updateWith(d)
+	}
+	return p.update(d)
+}
+
+// MarshalYAML encodes the resource descriptor, implementing yaml.Marshaler.
+func (r *Resource) MarshalYAML() (interface{}, error) {
+	return descriptorNode(r.descriptor, nil, false), nil
+}
+
+// UnmarshalYAML decodes and validates the resource descriptor, implementing yaml.Unmarshaler.
+// It follows the same rules as UnmarshalJSON.
+func (r *Resource) UnmarshalYAML(n *yaml.Node) error {
+	d, err := yamlDescriptor(n)
+	if err != nil {
+		return err
+	}
+	if r.registry == nil {
+		return r.Update(d)
+	}
+	res, err := newResource(d, r.basePath, r.SpecVersion(), r.registry, r.pkg == nil)
+	if err != nil {
+		return err
+	}
+	*r = *res
+	return nil
+}
+
+// decodeFileDescriptor decodes a JSON or YAML (picked by the path extension) descriptor.
+func decodeFileDescriptor(path string, buf []byte) (map[string]interface{}, error) {
+	if isYAML(path) {
+		return decodeYAMLDescriptor(bytes.NewReader(buf))
+	}
+	return decodeDescriptor(bytes.NewReader(buf))
+}
+
+// decodeYAMLDescriptor decodes a YAML descriptor. Numbers are decoded as json.Number,
+// so YAML and JSON descriptors have the same representation. See decodeDescriptor.
+func decodeYAMLDescriptor(r io.Reader) (map[string]interface{}, error) {
+	var n yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&n); err != nil {
+		return nil, err
+	}
+	return yamlDescriptor(&n)
+}
+
+func yamlDescriptor(n *yaml.Node) (map[string]interface{}, error) {
+	v, err := yamlValue(n)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("descriptor must be an object. got:%v", v)
+	}
+	return d, nil
+}
+
+func yamlValue(n *yaml.Node) (interface{}, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: object keys must be strings", k.Line)
+			}
+			val, err := yamlValue(v)
+			if err != nil {
+				return nil, err
+			}
+			m[k.Value] = val
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		l := make([]interface{}, len(n.Content))
+		for i, c := range n.Content {
+			val, err := yamlValue(c)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = val
+		}
+		return l, nil
+	case yaml.ScalarNode:
+		return yamlScalar(n)
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
+
+func yamlScalar(n *yaml.Node) (interface{}, error) {
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case "!!int", "!!float":
+		// Keeping the original text whenever it is a valid JSON number, so large integers stay intact.
+		if json.Valid([]byte(n.Value)) {
+			return json.Number(n.Value), nil
+		}
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return nil, err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("line %d: %s can not be represented in JSON", n.Line, n.Value)
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	}
+	return n.Value, nil
+}
+
+// descriptorNode returns the YAML representation of the passed-in descriptor value.
+// Object properties are written in the passed-in order, as done by Package.write.
+func descriptorNode(v interface{}, o *keyOrder, canonical bool) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range o.sortedKeys(v, canonical) {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, descriptorNode(v[k], o.prop(k), canonical))
+		}
+		return n
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i, val := range v {
+			n.Content = append(n.Content, descriptorNode(val, o.item(i, val), canonical))
+		}
+		return n
+	case json.Number:
+		// Untagged, so the number is written as is. Large integers would be explicitly tagged otherwise.
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: string(v)}
+	}
+	n := &yaml.Node{}
+	// Remaining values are scalars (strings, booleans, numbers and nil), which can always be encoded.
+	n.Encode(v)
+	return n
+}
+
+func (p *Package) keyOrder() *keyOrder {
+	if p.layout == nil {
+		return nil
+	}
+	return p.layout.order
+}
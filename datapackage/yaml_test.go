@@ -0,0 +1,118 @@
+package datapackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/matryer/is"
+	"gopkg.in/yaml.v3"
+)
+
+const yamlPackage = `
+name: pkg
+resources:
+  - name: res
+    path: data.csv
+    profile: tabular-data-resource
+    bytes: 1000000000000000000000
+    schema: schema.yaml
+`
+
+const yamlSchema = `
+fields:
+  - name: name
+    type: string
+`
+
+func TestLoad_YAML(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "datapackage_yaml")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "datapackage.yml"), []byte(yamlPackage), 0666))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "schema.yaml"), []byte(yamlSchema), 0666))
+	is.NoErr(ioutil.WriteFile(filepath.Join(dir, "data.csv"), []byte("foo\nbar"), 0666))
+
+	pkg, err := Load(filepath.Join(dir, "datapackage.yml"), validator.InMemoryLoader())
+	is.NoErr(err)
+	res := pkg.GetResource("res")
+	is.Equal(res.Descriptor()["bytes"], json.Number("1000000000000000000000"))
+	sch, err := res.GetSchema()
+	is.NoErr(err)
+	is.Equal(sch.Fields[0].Name, "name")
+	contents, err := res.ReadAll()
+	is.NoErr(err)
+	is.Equal(contents, [][]string{{"foo"}, {"bar"}})
+}
+
+func TestPackage_SaveDescriptor_YAML(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "datapackage_yaml")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+	pkg, err := FromString(`{"name": "pkg", "resources": [{"name": "res", "path": "foo.csv", "bytes": 1000000000000000000000, "title": "yes"}]}`, ".", validator.InMemoryLoader())
+	is.NoErr(err)
+
+	fName := filepath.Join(dir, "datapackage.yaml")
+	is.NoErr(pkg.SaveDescriptor(fName))
+	buf, err := ioutil.ReadFile(fName)
+	is.NoErr(err)
+	is.Equal(string(buf), `name: pkg
+resources:
+  - name: res
+    path: foo.csv
+    bytes: 1000000000000000000000
+    title: "yes"
+`)
+	loaded, err := Load(fName, validator.InMemoryLoader())
+	is.NoErr(err)
+	is.Equal(loaded.Descriptor(), pkg.Descriptor())
+
+	// Writing is the same as saving.
+	var w bytes.Buffer
+	is.NoErr(pkg.WriteYAMLDescriptor(&w))
+	is.Equal(w.String(), string(buf))
+}
+
+func TestPackage_YAML(t *testing.T) {
+	t.Run("Nested", func(t *testing.T) {
+		is := is.New(t)
+		var c struct {
+			Pkg *Package `yaml:"package"`
+		}
+		is.NoErr(yaml.Unmarshal([]byte("package:\n  name: pkg\n  resources:\n    - {name: res, path: foo.csv}\n"), &c))
+		is.Equal(c.Pkg.ResourceNames(), []string{"res"})
+		buf, err := yaml.Marshal(c)
+		is.NoErr(err)
+		var got map[string]map[string]interface{}
+		is.NoErr(yaml.Unmarshal(buf, &got))
+		is.Equal(got["package"]["name"], "pkg")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		var pkg Package
+		if err := yaml.Unmarshal([]byte("name: pkg"), &pkg); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := yaml.Unmarshal([]byte("- foo"), &pkg); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if _, err := FromYAMLString("name: .inf\nresources: [{name: res, path: foo.csv, bytes: .inf}]", ".", validator.InMemoryLoader()); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
+
+func TestResource_YAML(t *testing.T) {
+	is := is.New(t)
+	var r Resource
+	is.NoErr(yaml.Unmarshal([]byte("name: res\npath: foo.csv\nbytes: 10"), &r))
+	is.Equal(r.Bytes(), int64(10))
+	if err := yaml.Unmarshal([]byte("name: res"), &r); err == nil {
+		t.Fatalf("want:err got:nil")
+	}
+}
@@ -5,48 +5,34 @@ import (
 
 	"github.com/frictionlessdata/datapackage-go/datapackage"
 	"github.com/frictionlessdata/datapackage-go/validator"
+	"github.com/frictionlessdata/tableschema-go/schema"
 )
 
 func main() {
-	descriptor := map[string]interface{}{
-		"resources": []interface{}{
-			map[string]interface{}{
-				"name":    "books",
-				"path":    "books.csv",
-				"format":  "csv",
-				"profile": "tabular-data-resource",
-				"schema": map[string]interface{}{
-					"fields": []interface{}{
-						map[string]interface{}{"name": "author", "type": "string"},
-						map[string]interface{}{"name": "title", "type": "string"},
-						map[string]interface{}{"name": "year", "type": "integer"},
-					},
-				},
+	pkg, err := datapackage.NewBuilder().
+		Name("books-and-cities").
+		License(datapackage.License{Name: "ODC-PDDL-1.0"}).
+		AddCSVResource("books", "books.csv", &schema.Schema{
+			Fields: []schema.Field{
+				{Name: "author", Type: schema.StringType},
+				{Name: "title", Type: schema.StringType},
+				{Name: "year", Type: schema.IntegerType},
 			},
-		},
-	}
-	pkg, err := datapackage.New(descriptor, ".", validator.InMemoryLoader())
+		}).
+		AddCSVResource("cities", "cities.csv", &schema.Schema{
+			Fields: []schema.Field{
+				{Name: "city", Type: schema.StringType},
+				{Name: "year", Type: schema.IntegerType},
+				{Name: "population", Type: schema.IntegerType},
+			},
+		}).
+		Build(validator.InMemoryLoader())
 	if err != nil {
 		panic(err)
 	}
 	// Removing resource.
 	pkg.RemoveResource("books")
 
-	// Adding new resource.
-	pkg.AddResource(map[string]interface{}{
-		"name":    "cities",
-		"path":    "cities.csv",
-		"format":  "csv",
-		"profile": "tabular-data-resource",
-		"schema": map[string]interface{}{
-			"fields": []interface{}{
-				map[string]interface{}{"name": "city", "type": "string"},
-				map[string]interface{}{"name": "year", "type": "integer"},
-				map[string]interface{}{"name": "population", "type": "integer"},
-			},
-		},
-	})
-
 	// Printing resource contents.
 	cities, _ := pkg.GetResource("cities").ReadAll()
 	fmt.Println("## Cities: ", cities)
@@ -6,6 +6,7 @@ require (
 	github.com/frictionlessdata/tableschema-go v1.1.4-0.20220401172006-6cc5f3b2411c
 	github.com/matryer/is v1.2.0
 	github.com/santhosh-tekuri/jsonschema v1.2.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
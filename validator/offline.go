@@ -0,0 +1,136 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/loader"
+)
+
+var (
+	offlineMu       sync.RWMutex
+	offline         bool
+	profileCacheDir string
+)
+
+// SetOffline enables or disables the strict offline mode. While enabled, no network access
+// is performed when loading registries or compiling profiles: remote registries fail to load
+// and remote profiles (including the ones referenced via $ref) are only served from
+// the on-disk profile cache, if configured.
+func SetOffline(enabled bool) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	offline = enabled
+}
+
+// Offline returns whether the strict offline mode is enabled.
+func Offline() bool {
+	offlineMu.RLock()
+	defer offlineMu.RUnlock()
+	return offline
+}
+
+// SetProfileCacheDir sets the directory where remote profiles and registries are persisted
+// after being fetched. Cached contents are reused in subsequent loads, even when
+// the offline mode is enabled. Passing an empty string disables the on-disk cache.
+func SetProfileCacheDir(dir string) {
+	offlineMu.Lock()
+	defer offlineMu.Unlock()
+	profileCacheDir = dir
+}
+
+// ProfileCacheDir returns the directory used to persist remote profiles. An empty string
+// means the on-disk cache is disabled.
+func ProfileCacheDir() string {
+	offlineMu.RLock()
+	defer offlineMu.RUnlock()
+	return profileCacheDir
+}
+
+// Registering remoteLoader for the http(s) schemes makes sure every remote JSONSchema
+// compiled by this package, $refs included, goes through fetchRemote.
+func init() {
+	loader.Register("http", remoteLoader{})
+	loader.Register("https", remoteLoader{})
+}
+
+type remoteLoader struct{}
+
+func (remoteLoader) Load(url string) (io.ReadCloser, error) {
+	buf, err := fetchRemote(url)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(buf)), nil
+}
+
+// fetchRemote returns the contents of the passed-in URL. The on-disk cache is checked first and
+// the network is only accessed if the offline mode is disabled.
+func fetchRemote(url string) ([]byte, error) {
+	offlineMu.RLock()
+	dir, off := profileCacheDir, offline
+	offlineMu.RUnlock()
+
+	var cachePath string
+	if dir != "" {
+		cachePath = filepath.Join(dir, cacheFileName(url))
+		if buf, err := ioutil.ReadFile(cachePath); err == nil {
+			return buf, nil
+		}
+	}
+	if off {
+		return nil, fmt.Errorf("offline mode: %s is not available in the profile cache", url)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status code %d", url, resp.StatusCode)
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if cachePath != "" {
+		if err := writeCacheFile(dir, cachePath, buf); err != nil {
+			return nil, fmt.Errorf("error persisting %s into the profile cache (%s): %w", url, dir, err)
+		}
+	}
+	return buf, nil
+}
+
+func cacheFileName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// writeCacheFile writes through a temporary file, so concurrent readers never see partial contents.
+func writeCacheFile(dir, path string, buf []byte) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, ".profile*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
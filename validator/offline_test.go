@@ -0,0 +1,109 @@
+package validator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestOffline(t *testing.T) {
+	t.Run("RemoteSchemaNotCached", func(t *testing.T) {
+		ts := serverForTests(simpleSchema)
+		defer ts.Close()
+
+		SetOffline(true)
+		defer SetOffline(false)
+		if _, err := New(ts.URL); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("RemoteSchemaInMemory", func(t *testing.T) {
+		ts := serverForTests(simpleSchema)
+		defer ts.Close()
+
+		// Compiled in memory while online, must not be reused while offline.
+		if _, err := New(ts.URL); err != nil {
+			t.Fatal(err)
+		}
+		SetOffline(true)
+		defer SetOffline(false)
+		if _, err := New(ts.URL); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("RemoteRegistryNotCached", func(t *testing.T) {
+		ts := serverForTests(`[{"id":"schemaID", "schema":"http://127.0.0.1/bar"}]`)
+		defer ts.Close()
+
+		SetOffline(true)
+		defer SetOffline(false)
+		if _, err := RemoteRegistryLoader(ts.URL)(); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("DefaultRegistry", func(t *testing.T) {
+		is := is.New(t)
+		SetOffline(true)
+		defer SetOffline(false)
+		v, err := New("data-package")
+		is.NoErr(err)
+		is.NoErr(v.Validate(map[string]interface{}{"resources": []interface{}{map[string]interface{}{"name": "res1", "path": "foo.csv"}}}))
+	})
+	t.Run("LocalRegistryStillWorks", func(t *testing.T) {
+		is := is.New(t)
+		SetOffline(true)
+		defer SetOffline(false)
+		_, err := New("data-resource", localLoader)
+		is.NoErr(err)
+	})
+}
+
+func TestProfileCacheDir(t *testing.T) {
+	is := is.New(t)
+	dir, err := ioutil.TempDir("", "datapackage_profile_cache")
+	is.NoErr(err)
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	schServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintln(w, simpleSchema)
+	}))
+	defer schServer.Close()
+	regServer := serverForTests(fmt.Sprintf(`[{"id":"schemaID", "schema":"%s"}]`, schServer.URL))
+	defer regServer.Close()
+
+	SetProfileCacheDir(dir)
+	defer SetProfileCacheDir("")
+
+	// Populating the cache.
+	_, err = New(schServer.URL)
+	is.NoErr(err)
+	_, err = New("schemaID", RemoteRegistryLoader(regServer.URL))
+	is.NoErr(err)
+	files, err := ioutil.ReadDir(dir)
+	is.NoErr(err)
+	is.Equal(len(files), 2) // schema and registry.
+
+	// Cached contents must be reused, even while offline.
+	SetOffline(true)
+	defer SetOffline(false)
+	v, err := New(schServer.URL)
+	is.NoErr(err)
+	is.NoErr(v.Validate(map[string]interface{}{"name": "foo"}))
+	v, err = New("schemaID", RemoteRegistryLoader(regServer.URL))
+	is.NoErr(err)
+	is.NoErr(v.Validate(map[string]interface{}{"name": "foo"}))
+	is.Equal(requests, 1)
+
+	// Profiles are served from disk, not from memory.
+	is.NoErr(os.RemoveAll(dir))
+	if _, err := New(schServer.URL); err == nil {
+		t.Fatalf("want:err got:nil")
+	}
+}
@@ -1,4 +1,4 @@
-// Code generated by "esc -o profile_cache.go -pkg profile_cache -ignore profile_cache.go data-package.json data-resource.json fiscal-data-package.json registry.json table-schema.json tabular-data-package.json tabular-data-resource.json"; DO NOT EDIT.
+// Code generated by "esc -o profile_cache.go -pkg profile_cache -ignore profile_cache.go data-package-v2.json data-package.json data-resource-v2.json data-resource.json fiscal-data-package.json registry.json table-dialect-v2.json table-schema-v2.json table-schema.json tabular-data-package.json tabular-data-resource.json"; DO NOT EDIT.
 
 package profile_cache
 
@@ -190,6 +190,82 @@ func FSMustString(useLocal bool, name string) string {
 
 var _escData = map[string]*_escFile{
 
+	"/data-package-v2.json": {
+		local:   "data-package-v2.json",
+		size:    27876,
+		modtime: 1792363123,
+		compressed: `
+H4sIAAAAAAACA+0da5PbtvG7fwVG8YztsXRnt9Np62+OHaeX+M6u75J2ais9SIRExHyZD8uKc/+9uwAo
+UiRe5OkcO2k+JDpiCS6Afe9i8/EWIZPbxTJkMZ08IpOwLLNHx8c/F2kyk0+P0nx9HOR0Vc4e/PVYPvtq
+MsX3Sl5GDN96SktKXtLlW7pmcihgxTLnWcnTRA9QbjPxarr4mS1L+Sxn7yqeswCev4a/xZMirfIlKybw
+91wAZXmasbzk8OwR+SjBmgXIB23cXubpikfyq2Kkg9pFyEgmYUi6ImXIC1KDpPlR816NcVHmPFk3z1dp
+HtMSR6qcT8TTKzk4SWjMtEid4YAJo8cJ4QFLSr7iLCfyc2Y89j7IA+3nTp6aP0bUhm4JbDbL37OAwIrI
+OkoXNIq2pEr4u4q1MCo8cam/3kfnQvwwYxRWMU1mOaMBXcCpiPc8P7o/V//TT1vjZgRK9qEkradH5JTm
+b4N0AydTEJYsgSiBkgNPpMI0ZhlSvg6jf8AgsMbaTqE4BUkTIE9GNmwB/6Ul4pKziJZwZGWqKBcZLZOM
+5ondezhS03b9qMbMW6XIQ01CkipeANEiBXlz0h42Szj2kunJ+Ikas20UbAArudysTciXYRcPsqEFUV8Z
+xN048wyn7iCcJvDKooKpCz3WbQAb6u2ZmgO1bSDNc7ptHsc8OSlZjGg83D3k6kmNmAG13Sy6Q25hZkVM
+L9cb7F62ZffD1phGqHdwbT80y5JxEsVClvKfqzbkZM3fs+RsX7R30foWYciemDfhJj8n9pjyBH8ik6Pq
+IMDKwFdAySHQ7ILBjOLbozFf0ZhHWwfqzwTQdXAXn+E0kosQomq3Ep6ELOdlMXoNGS1DC/YvcdiF9qpC
+zfauohFqtID88Or5lABtg43y4vzk30QYA/ih0ViCNcIjC5rfiHEHngkR0xAaBKCbnVvWGW0kl0TGiCsY
+eDThv9CO0uyi/KIN5sS8PSlQAl2tkCTwr0Y5tMTK6I3O06gnNNpYvxLjdnRR+Ip5BPW2hV1IA6BY+RhV
+BqIvjERmkH8W8WwR0kZR7dwM2I5but/1r6s9XfWWbTdpHuj11Pf1oFnZR7wocf31PJK3JdiCyUM1Gx9j
+tZV28fsL47HJvDqJbaYVmtoIgFotZxlawEnpWofObIn4kiUF0+/s83rQpv3VDHeLe2BTBWBDtawXhQsa
+fFm1gFMItbbLdc0BhafVFFBo2i08qx1Ak+2L1c7Fk/90uLfnCLbGhE+193BuEg4DZhVaxTzr7vfcy2hJ
+7Br2RQaa/ClbgcYUEqXe08bHcgis0x/OL8AeIDQh7rmmpGDgQEjHvqbTI0AdqKx+TTj5w9QL7FjJcoHO
+T3dfz+jsl8ez/zyY/f3ov/N7929/6fr78zA7HeK8iY5oXV10BV/tQCwi8FIIjkuU7HsvEcm8xZQwCqJo
+mcYZ6HAQkBtehkIJvt6Dn989Rv9zVuN1fI8UGVvCGS2F6jycyLqdMxQhk6+OGxouxMdrXPR6wrZf546N
+ElYC3RA1RyMB6+UOWt4DL4kscbIKZHVUSPJ/NEfs/67AoV2BfizIFREy80odElL2cpdHxku/WwrrSYv3
+m1hwiYR1bo4HXwjCUwBmU7cN1mf1qVYYtt8BWSgwkZFpD0nY4VO9tTIBGo2Cot6mnTVi4ltNZNweHb9O
+hNx6ni06qxdhwmlv659JWBt++xqs/3atxgyY9r00o49m8NBsqDsY5VxSBagToDKBrHI3BfEQlYIxMU1P
+to+xnrt2nUkDmC1bS27DJnpFdGjHWmL1R7oXbd5vR4BZNJVbW43XWKPQ1KcqdMjqkhY2lHvpi0Ogyz5Q
+kHiuff1GQXmgCSpNApP3NKpqSmA1IbiPWS7AesoI4kYFBRzOVsc1psr3vkQ/61I4WgpHpcskp+IrPlur
+gWBJFWvYU9qopreQ/UR6RT/Gk5KtTYOYvNCPiIyG8R3z6JbR3DwSp0nP+FLDizSNGE30g1qJVsfdWZql
+sErjKGat9YO6SJwawpiWYf1VTg08J8MYk97zuZNqd6aYjW6fSSA/ypUz1rRLUjGIGho9/DpnOIxinavI
+g9WFm/1ePX1GBrAgggs2vAGdsIQtKXMK1FM4kH7SgvQ9gChKNyoPUr9LaA7qu8qyNMf87GI75BiMXGDX
+zl3trxtvfaVmRA3UlZbkZa73BiYGe+s5S9Yab643dy3l/OemH25s7iYG5phZT6bmiZV+cMxqlmrWPEPn
+PGuoMq+YN4Ioas91roWZmodQA4/l+o1HagdgH5ZRVfD37NQ1VQPZmrMHeOWULzEvCjjhH9FKcEmYUwlL
+FLBbyEhAmWzZhCyRFShArMDmtb9Q+6VFmFYgYhYij1XwQMDQglwmVRRdTsmdJC2JSnTcEZGOO4uIJm/v
+SAun0MolXcheH2QfRqOmhNcA/ukfjpGwPgmmFgPG5Zy14zB4FhPD+Nw4uVM77E1vA/Hce+Ne18YVXbDo
+YB+6NeS5lixGGGxLsILXac6dbP2kAfRx0kToCviWiYxqloJMWNS+ULHvDE2VUSfq8cSWsuCT8qmHLvFl
+EAvRWxyfjotjYozfTj4ccId+fyLktQXG59x9zt58/n8oGfUiFxrfW1SR+gW3yPpXyEAiSbHUfFD4Oamc
+xOrTGJwCTVwH7FAvO+oCAMcZUU2pxyV+7dKKuInvHTxv5XcnPbktzhWNCr99eoaQ194o8b0vcqcCtuQx
+jZ6ENHcGWgUkQVC6LLXBNGMR5CZMizqWyQtSFbIsu9lCShQmRISxROJIWe8yrneQSMc6T6vMY63fItxB
+Vyq+TAK+5mVxQ4tbgLQ5kzFQ++q+RrF0ZoqW2kRbFm4LvoRDwjpAhpGcOuclgzdxVZQq5CMtNBF+Kzvh
+n8OIwoBFPOYlc9NtDecXsdrNK89usZWVfTIvd5CDQpb2iBCiVCBDo/QqCC+kBv4xEPcDhuOtIXdbrN4a
+A7fH3R2RfLf14FM92kvWntJyGRoztjJJSySQK5W8odtOdl2+jRwb0yxjGLQWtwyYvM6yq3Dpn7DtbPWn
+inmsfsqUYQFI92FRLcA76z/FSvb+84yC1Uz3aivmna1Y0SqSVRgCB+12ZzloiHz7PdtacvYChCCMNS9O
+1GSYG0BBXaeYReIVa1wSIlQ2MlEzUkzbAr6os2AyWAgOqKos3MqisBzEYDtl3TmfNGEaj3RYgfPU7129
+9WG1PTQRUK0kM1lYdqFoZLS59uQlLnCo5sqIH+RlKwFjP/m6UlvxlryDAQymrmuV6VpovBssiRhZA+86
+kYMWybfFXJozvk6s279f3SFfcB+G8a2b3/zh9SGrfrmNemcFHl6yvFbxiLbuxyoovAIzA5Mrnzyp8smD
+3yMiCM35WiWdOTHpCEp1CteskSivJKeq8j145s1Co046NVPXgBiSMVTlMbONdj3pdxANe9HxDcbP5l7U
+f3VNC9iW2/PK6n0++TxDhmCoTXUom2h601iMl1eG8Pk4cWUPlo+WVfbItc+01+CVueNyivAGnoJHhAdg
+LsSuIWy3LiRkICHtN+K7VdRfVF10yGjQi/W0OkXIYRtKTTkxrysVATmeLKMqEDXG8hPotU0JjcAVL1CY
+SMgcXIaWO9e9UaIJYBn8271Kkv4CX6WbwrFIImD8VlqK27sbFWZsUqfyY/5GtsOW1sd6WsUqD423Kpqd
+2Rc1D50+oVzDdylPXBsmYPw2LITNigEfHvGEzRRF4GnDEH0v7sL+nCoiAMWCkKDXGsoZEoNpBT2InuiX
+aRyD7rISxRMJM4QqNiJ9AfC7NYEKTWNeYjXeKk/jXXzpN6IQ22Zowvf9zdCH7m18gjGAZCuYZcHWPBEN
+G9T1FZBdYNrOlvWkKtY/FeNpJWyMJWOBjP/DThQZFfdgaFUoJsSwUI4XmZN6v8HTTXt5QS9RaIp8u2Le
+mt4lzYpA5aOLg8gB0o3gw8hXwTKAc8rtrlu+Ky18aCL7qX6BuEsXLIcp8KPGVT7HzWzB+YtE3bqRAkT+
+As6mztqUanJ2DdZ+k79J9Mt8V6Uls5LzPxFiMDFTLbV2ThY/LvIyNdjI1U0MNJpWYJcI/M1UKmCIBPI/
+vJAmQYSog10hthAUurD+7VHwsXoZUKGZ/Zi+ESA3c07y877HpF0BekPnis6NazgDILKD8j8NnHzHRVNh
+YKirK1Ny+ebN2eUIhIu3PDvBy4s0OkdJakT6HACJgiQS1B9zVEk5SO5y17qkkd2Egx4Bo7rEoH5Tw06b
+7OA4KhOFAobchuw0Z7O6JYCH2S171sGKKPnu/MXZXqpIXt8JUxn69pFsWnwNSUxX+tKVuBRWiRbxaye3
+tNH2fv313Lhca/xbLNcd7w7FRaqmLoB9KJGvd6WNaHxJnGwHM9z+sl4hbrNeyFiprSJocR3C6AoIdKtV
+Te/EkYrJ1c8Mbw3KB+5D1lmPWtuxv5T+nczeQrp3MrXLEGnBay3Cxk0iIGDPrQxAUd6PFT8RrQUt2Bj8
+tKGLvT4SztYaxtDFHtToG9/tG7vNfe9+GHpvW43RrV6jG60o8JwL92liDQl9/hfU9/vj9Vu59gwNG7M5
+2cze2nWYw6FXsf2WGJaGGH1vaZcHIu0KDE3/AzDdGBdFU1S0x8AHdOc0dooLEEAEmuS40H6gDTi22zgp
+DlIyoMv7a/bqpgsK/CPj+jJJA8rDqwkEbxq9Ehy0EcJJIrx4/eEfGeS7zVIabiSxA5lEUu0498shhrRd
+Q/QsZO8c8unrY/S9ktptUoaSVi+o7xPYHx/c/ww2ql7MwJ3SdaKw9aAY033CM5xm6jXh7jJxiPbY/imQ
+fqvsTri71y7bRFjXa5nt78j3Wop1DPBOWzETup6txQaUCj0Y1Lqm12rMUAKubzfmUXZkaTvmUTv0hTR1
+0bYkc1hhJiRHtyYbhbm+TVknCKhpVWYwc63tyjztIUvbMv0aTO3L9lehb2Fm4kuvNmbXKge9MiQIeh1l
+u6mBTldZ0wqMnWUbK8vQWfbgdYnmTrOmMlavbrMe0sd0Nfbj8NKQfg8trxq7EV/qd6V1V/QfvJ/X4G61
+uqP8JB1rPcWKf+fa35uM/zyV6PBK7X4HJWvvJG3THtxY1Tqpjhn5+Zz6GkGRSbF5wKcI4ZcsEJO1vOE2
+auQJsJBgpK0KobSgseofiEik9F+fPD57PL+LXFQAG202myNOE/m/c6JFwdcJVhEUx+L1Gb6+9/voQ1jG
+0b0B7vceWx3dv3eM/7qt3y70EbCUwJxurAG8zrGebuBJmhK+Vbma/U2P+GJbWlTy12LUhXLBf+kfLFZd
+ickd6QG910QLc9TvHzjowun06V/wfzISagjuJAmwaymgLMJ9CCXyhBFeAC/DuGg6oH7cPbx69BEBrxSL
+jSWj1z89mt9/9JrOVo9nz0BEz+//2vrj45//dPXrHon1G8Xeurr1P3JwuTrkbAAA
+`,
+	},
+
 	"/data-package.json": {
 		local:   "data-package.json",
 		size:    22639,
@@ -257,6 +333,71 @@ p+8Xefp+kQaXLv/q7xe5r6v6m1+ZzL3Us/T33f8CAAD//15vwJlvWAAA
 `,
 	},
 
+	"/data-resource-v2.json": {
+		local:   "data-resource-v2.json",
+		size:    21268,
+		modtime: 1792363123,
+		compressed: `
+H4sIAAAAAAACA+Uc2XIbufFdX4Hiusp2maTspFJJ9Ob1kdXGkh1L3qQicyOQA5Kw5/JgxhTXq39PN4C5
+cQwpybtO/CCTg0ZPoy/0AfDLASGje2KxZhEdHZHROs/To8PDDyKJJ+rpNMlWh0FGl/nk8Z8P1bPvRmOc
+l/M8ZDjrOc0pectEUmQLpsYCJhYZT3OexD2IqZ6+TeXsZP6BLXL1LGOfCp6xAJ5fwHd4EtOIjeDjTI4n
+MXu9rAa/yL+mafJpSvP1SH+dyf+vx0PmBUBsZ15FQJolKctyzgTMUXga/KsQV6x5kyVLHiqmKORtxpyv
+GUkVDEmWJF9zQUqQJJvW80puiTzj8ap+vkyyiOY4UmR81FylYp2JqFMcsFH0NCY8YHHOl5xlRL1uAB0R
+j1+xeAUcPyJPWmRIMRh5gwNWMkjGlixj8YKRPAHGMIJyIbBexaVMa9OYUEEYB4CMUIIvwwdUUz4mAE9j
+QrOMbpHDCCAQQo0LfPaZhjwg794ei8ZC26rWVBsXHyy8aPDDikmS2EN0nLMIde1Ja4Drp01EbqKsZCnt
+bn9qWYuyB5MA0aqtAjyOQx7bhDZta6qmu/+Gcxxw2Q7OVIbDGsi92sriImobfU7nITN6i5HDvM9xFjlT
+AHZVboKZmLGn0nlV6x7YEMJ/dxiwJY85UiQO5VI1zT7Rcxqic7av/bmGcAlJQWpcZvf29RZeEuxZeblM
+w7rlB7uw10VE40nGaCCXLefZVbLN7hYug8E1xu0E5Oyq5jI8nZITmn0Mkg14dnCV8QK0jq5YMJCodRKx
+FOCNFP0Ag+QNjroUAFGQJJZWumFz+J/mRFpASHMWKAePOx86i5QuPgLCgdQpExJG4s70mIu0jG6IxuG0
+TLOXbnrox9XDvnfu0tRwzX35qXcTjAmmTUBDwFRT8aYZmDQ2C1PE0qGpu4mYFX0/dffYb8uC+7GCM2Kw
+EbQswnBLPhWwqUMIg/v6KxUFkDevz47/Jfkqw4C9qQTHyUMHmS/kuIfOmEg0hAYBqJvwEdMZrcM+RYyV
+1s8sE2130qX2Jw3hphdtReMq99uujfi52PO51y1bDvmCxcJizK/KQZc1awwPxENSxAFEhJs1X6xb0QH6
+nbSYh1ysjS7QbuBPBhm4ptNp4ZrM2uGY9kSnxdN429kvSVfAxuRGj5VZVf1vZtOgHbA2Ey4D1urzbJB7
+6qQvXT6/TllMnle7e8XTOnnxaPTJu7NzMmeYHfhxjYlgsI+p9LjU0ymQHtcBhkyVdzNj4FjOMknOzw8u
+JnTyy9PJvx9P/jr9z+zho3vfup/8fWwwHqdTedK+y3mphlwORzJJoajyd38e0iIgYhAZWzOgExwl3jxI
+ImlkQ00yyDNQcanoW53qNqDBD2IEtoEEmlwcPz19OnuAWi5AzTebzZTTWJWAqBB8FUdgD+JQTp/g9Nbn
+6dU6j8KHA9KvltpPHz08xD/32mzBODXAWSauvCgHvbIp0ewgnQbOJS1CVVzJl5O/tAmcb3PLVvW9HHGR
+JvgvfUERDmLCqQbSeJyzFbi0dmROhbmy8gMOuN5/8vxPBGcblOU4DvgConKSyJIKQiH7aLhKMlCSSChd
+wS31S/Xw+ugLAl5rU9hVBS5+Ppo9Orqgk+XTyUtwf7NHvza+fPnjH65/rdTjQK9/1Mjs6lpcM7e9s4R9
+TBZJlIZgG3nNjIvmnNkDlWyq0sHhQyJStgA3C4zFjaLPns4Ob6lMgp8OA1FVKWpmmjdRQ2XSXZ28SYVy
+aJ5eLsJGU4v1LxWsiz4IpC9lzHaJ5PVnE8VaYaH0NqpudtI9m9uZ0gqG9UrJFnQBqExSeYiuwNv2ul5U
+uE/Y1w1IbOGYPSRz1JZd2zriqk1Lrn5qmmjf2XsBhyPo8Ace+wcfe5FpLvWYiDUVfVwk98o/t0Euu6Lg
+8Xx8faGhBpAJ6a8CxpCkKDWBlYrgF3M7YDJKuRk0uR2cDIU+su0myYKxThovMUG4lIGTplHnvcpSccoQ
+1hogetXnxph1FppfEc17aU3pm3SAYBwMYD83j+Q8YvY59tEto5l9JEriXgKih+dJEjIamweNHk2PrViS
+JrBK6yg2Lc2DfR9fDWH4a1l/kVGLzan8e9R7PvNqbS/ZMOltJ+1wa67OPbTukkQO4g6NqWlZc91NY72r
+yILlud/83j5/SXYwQQSXZngHe8ICWJJnFLRHeIh+1oAcKoAwTDYYIjfeQmgG23eRpkmG2dV8u4sYrFbg
+3p27u79pvPGW0hANUNdGlS9i/qlgd4C42Zf04G6lQYNw06s7w12nMB7MZjW1I9b7gwer3as5YtiePEuo
+PCvYYALR1Z6ZUgu7Nu+iDTxS67eK1A3ArhZhIfhnduJDVUM2cPYAr73+JeJCgIR/wijB52FOFCzRwH4n
+owBVz2yzZrHq4IGygpmX+UKZl4p1UoCLgZgFnREPJAwV5DIuwvByTO7HSQ7ZHBMszu/Lat/9eUjjj/dV
+hCOMfslUazZXh3fTUXM6tZP99IVjVayvQqkjgPElZ82eDcpiZBmfWZF7d4cWehfIQN5beV0GV3TOwlt7
+0cEuz41qsUfAhpUwrHF5zfpZDTgkSZOlK7BbJsvGaQI+YV7mQqKdDI11UEexXC9ZyoKvaqcD9pKhBuJQ
+ekfi00lxbIbx2/mHW+TQ/54LuXDADJH7ENnb5f9/5aNeZ3LHH+yqSDnB77L+uWayH4BuqX6hzHMShcSZ
+01iSAkNdB+LQQXHUOQDuF0RlTEdA5BLfdukk3Gb3Hpt32rtXn/wR55KGYhifXiLkjRkl3/dNcipgCx7R
+8NmaZt5Cq4QkCEoXubGYZjitJSmDyDwRZS2TC1IIdaytZiElmhIiy1iycaSjd1XXu5VKxypLinTAWv+G
+cLe6UvlmEvAVz8UdLW4O3uZU1UDdq/se3dKprVrqcm3peiv4AoQE+VOOze6q56WKN1Ehcl3yURGaLL/l
+nfLP7bjCgIU84jnz620JN6xiVeFVsptv5SEAtUBxK4JCkx5QIUSvQHat0usivPQa+GVH2m+xHO8subtq
+9c4auLvu7qnk+6OHA9u3a0ez9oTmi7W1Y6uatEQB+VrJG7rtdNfVbLTYiKYpw6J188JHdRq2L2HnJQyj
+VLGP1W+ZMjwE1X0oijlkZ/2nEB8bnqcUombaOoc567CiOkuiaDCyO81gh8i2f2dbR89eghCEcfbFiUaG
+vQF01GWLWTZeO7di6hExbjp4UXbBVLEQElB9JG5LGF2sSQZusNmy7sinf6rflGk53UvHuVjnmqMPZ+xh
+qIAaPZktwnI7RauhzYySV7SAUO0nI95JECJh3JKXPrEUqw7m0MDUS2C3Xskd7w6PRDik4pCJVyJWebh1
+yO/mkozxVexkf/t0h5rgF4Z11t0zf/fzIcv+cRs9R1/Fu8nhEeO5H6ejGFSY2bG58tWbKl+9+L1HBaGW
+r9PT2RuTnqJU5+CasxI1qMmprxrfeufNoaNePbVr1w41JGupagBml+4O1N+ddHiQHt9h/Ww2SPuvbxgB
+u3p7g7p6v59+nqVDsGtMdVsx0fiuqdjfX1nK5/u5K3exfG9f5a5cD0F7A1uZeW5VtK7a3vntYdsp6m/q
+XPSa0aBX62mc6VfDLpLq48S8PKkY4q2CRVgE8oyxegVmbWNCQ0jF5S8hKMgMUoZGOte9fWooYFny29ZJ
+kv4C3yYb4VkkkTDDViqv9gHZqt5St07Vy4YH2Z5Y2lzraRxWeWK9BVVzpu1qnnhzQrWGHxMe+xgmYYYx
+bA3MioAejj/PMNEagdKGIfpZ/tjGh0QrAWwsCAn7Wq05u9RgGkUPYlb6RRLhnSKnUjxTMLtoxUa2LwC+
+WhNsoUnEczyNt8ySqKov/UYa4mKGoXzfZ4a5dO+yE6wBxFtpLHO24nEsi/nq+gr4LghtJ4sSafXrKTie
+FDLGWDAWqPo/cEKkVN6DoYXQRohloQzvk8UlvyHTTXp9wUGu0Fb59tW8+5WQxopgyy9/TQaIrh0fVr4E
+SwHO67ftv/JiU/uxeYHIpXOWAQp8qXWVr5CZDbjhLtG0btQA2b8A2ZRdm1wjZzcw7ffZ+9i8zE9FkjOn
+Ov8DIXZWZmrU1o5k8eWyL1OC7bm6kUVHkwLiEkm/XUslDFFAw4W3pnEQ6juLkoWwocvo310F33dfBlJo
+6hbTCwlyN3JSrx8qJuMKMBs603puXcMpAJEKarg0EHllRWMZYOirK2Ny+f796eUeBIuPPD3Gy4s0PENP
+aiX6DACJhiQKdDjluCVl4LlzeZEBtan23YRH6uIxFvXrM+y07g7up2XyoICltyGDcVdnQwMMCLslIK6I
+kh/PXp+2WkXq+s46UaXvIZ7NSK+lielrX3p/pQqjEiPhN25uGavt/fPXM+tynfVvuVx/vXstL1LV5wLY
+VY52XR1txOBL0eQSzO7xl/Pmf9P01ozlxlMEDatDGNMBAtNqVeahRCqR648p3hpUD/xCNkWPxtixv5T+
+nczeQrp3Mo3LkG3BGy3CZU3q99WcvZUdSFT3Y+VHJGtOBduHvnbpAm+WH1wf/BeMgzdOFFMAAA==
+`,
+	},
+
 	"/data-resource.json": {
 		local:   "data-resource.json",
 		size:    10172,
@@ -638,14 +779,78 @@ At1CJLwP/Sr1upe5fvXMFjPVg/m/9L+yzLf/PwAA///84Dg7EpYDAA==
 
 	"/registry.json": {
 		local:   "registry.json",
-		size:    1480,
-		modtime: 1511055225,
+		size:    2446,
+		modtime: 1792363130,
+		compressed: `
+H4sIAAAAAAACA62UvW6DMBSF9zyFxdRKAUsZM0edq7ZbVVW3xiRunYCwk6Xqu5c/U6C2r0EwMHDPPeI7
+OvbrhlTPd/MmJBJptCdRChriAtgXHHm0NTMttOT1+FCNyeN0rNiJn6Ge0+F+8qnyy1T1XoA+odKCM5EJ
+BlpUnyvxSetC7SmtByrJSsHqieRK1S6JyEduNGqMfrY2Qg0fVwlljJC+tDKCEtv8EPKQFVsC3gBspt4g
+MqEYSCyHh0aFx2BxQ1II2JhfA4upN4RGWHKVX0vmLPzTv/m08cYhpPIu7cLO9z8f2nkP7aj0Xmqr45za
+r5eCHc6fhuRxB2MNQXLyPBmP2ft9HNkpXUTau+Gt7uof33bIRU7ubrt79DavfGZc6Ba1i7deM+c/L49U
+abikUKZ02SH28JpGI8ADpznneTVkY0hDS2xHHvbYTTzxmdHnNXiDG90KUwGSM+3jPbQSDPjPKYjYLV+I
+3Bka5s3bL2WoQUmOCQAA
+`,
+	},
+
+	"/table-dialect-v2.json": {
+		local:   "table-dialect-v2.json",
+		size:    3806,
+		modtime: 1792363123,
+		compressed: `
+H4sIAAAAAAACA71XUW/bNhB+z684eH10mvVpQN+GdsDaFWk3520p0LN0tphSpEpSdY0i/313pCzLDqUo
+67CHAMp9R/L77o7H8/cLgMUzX1RU4+IlLKoQmpdXV3femstkfW7d9qp0uAmXP/9ylWw/LZayLqigSVbd
+4FoTvFaoqQgJK8kXTjVBWRM9KoLkVSYvODhY97zbbd/Ezez6rt+lcbYhFxR5Rr6z5YRtMgyJfHB2o/hz
+eUAyNJrkA3YDoVL+AZG0Y0fGB6fMdhHN9wldVIQluez5vydo7PhVQ4XasBpQcjhBJKJMoVv2BIS0NTi7
+WwLqHe7Z6DtP54MA7N4vzRBeW6sJzZDCBlsdGAuupYyQv+zOT4iBiD+uSDgJPdPWa3IeNtZFWzokwxSd
+w/3RrALVQx4DT2UCbQdhZahWRtVtzeiLznifUfx3v+Dg9TETgLdWmakARPzxAFQsvuZzlVaGLrtMSpYY
+wq8EwcKd7ZLnyIun2Q4y7sfLLyNtAadFWdi6JhNGk/kq4XOzuaswVtuR+5pvTK1CoBI2ztZRRokB/4fM
+5oS+qtBNChUHLMKs2xhELZp9LOA1bZUxkpudClXqEZYzWhw2hJSVZcRtG7ijUEFUxiUVq/UNFrSEAlvf
+XQwmpByBVEYXS7U11lE5s+WUpBUHf6TrvO7RMam/woA9fWnJFJEIEzw2GNIlgw37TfbC3s6ZekdmGyrJ
+Va5El6ciRP0NOV4mB2SVvJMADXzmtZ2cNslo3XLL5FizylLUhm5j+hfX7dbdmlM5X1obaLQM/xT0SUWI
+2So7y5IcKtbe7YkqFmd1ZVt+kyPXfGVFHJLDvGRUaEotFPl9jSHix854VXYV5n/wzeKjsRkP+28R/u/j
+no6dE/YTtqbVetXVZJbvNTtA7zEvwrJpX+nL+NDSN6wbzf98ur29/jSTnP+smjdGBR7IVtKxsgRX7ASd
+FyS3eSyltTvujAEFl2o49kZQ3KN5EAyk98xfa7uTkCP0fe5pVbJB7U/LpBsc92NTYgIfGROjkzBHeLt6
+fx2fO/C2dayAxRQVv/lc0YfRx8+Mu7yHNwl/yO0NgxDRKXKyfRpg08l5kjN6A5n41B4HpbPHG/p5PDtA
+iZQ/aO/HpUR0Sspndug7NH0Lcr/gK+qW6ygOGun8sQDPnzWGqTgfKnxFFK7j5Jq/BYJD5zClJk2/KR1x
+0+6TLwKWyTCdoPOJ6ME8dEoZa5oiLPAkXXb4AbK56g7yKy/LKf7+m0kn7pI+hcIaPT2Fy4X83V/8A7HH
+1b3eDgAA
+`,
+	},
+
+	"/table-schema-v2.json": {
+		local:   "table-schema-v2.json",
+		size:    10220,
+		modtime: 1792363123,
 		compressed: `
-H4sIAAAAAAAC/6ySsU7EMBBE+/uKletL0l+NqBHQIYQWn0MM5hJl9yrEvyMHEpGw8foiGjczO/IbzcMO
-AOBjeAGMP5oDmCMyFh3aN3xxZj9q7Dm4KF8hI9wsZbKNe8eoV7/vy1dqT0vXU4fcqNbOWV97i+zbUzQ3
-zB0dqioKVNa9t1EJjiimlL6dpVVmCPrcS4SMz+eAfaGQ3n/bQCWW8hTynBOpgWQBUmiyiNqTxaD1cD24
-9BqENKWFjIvLZyCEJksYjL2j9tzb1cHf/tGXix8Tcia/5t24+enzuZtP0M5Gn6QWEy+Z/f+1IMOl2wiu
-+IERSwgO7hbynH2615FXrZtIp7Rx1bvHrwAAAP//1x/pvcgFAAA=
+H4sIAAAAAAACA90aXW/bNvDdv4LwBmQD3GR7GtC3rl2GYUs7LOn2UBgwLZ0tthKpklQTI/B/35GUYn2Q
+lJy4RbcCBay74/F4Xzze5X5GyPxblWRQ0PlzMs+0Lp9fXLxXgj9z0HMhtxeppBv97IefLhzsm/nCrNNM
+52BW3dB1DuTacbGoFFQiWamZ4IbgBWmTkI2QRGdMEQlKVDKBBUlEUeaMck1umc4QC+Rde83yuwttPp0A
+F98TVULCNiyhZo/zWqBdaeUR6/eQaAeT8LFiElKEv8NvhGwY5Kma48fSUpRSlCA1A4U0947moBIHaJ/2
+Tyk2DH8uGkzvsDcoe+loiNi4gzYkQp4f1jXiKi0Z384teL/oCOnbv6PKS0cXkuUFJysqJd2tjCjDlcSp
+SnmksssO4ILx3zQURqQfH4CshjRCxsV8YDaU89pZExRB77CCMW6dwBqd1K7YZuCxdY0ZWLyGc1rA/AGw
+bK3weEB7TRfWPuNrg150kQPXNzwOLm9Pd95f5HMF92/fpnzYOSTQjf0xJlFWFZQ/k0BTq127+tEidbmH
+BHvVohoTT8OdJi3oo0WDO4pZJaavX2qKEZE4qVmRTzSvGmtCY8ywuZygQWsZdHxrk0wMF/IBdrdCpgty
+m7EkI6urt9c3K7IGDBgnk001tUh2yZjaeljgVdELGQv3UpvQqIo1yCGccQ1bHyKlGoZQzQrw0/oxO6DS
+Dy0E19kQtRYiB8qHiEHmqOFbEKXAU3gx5mYcIrq58gGcM+XhklaSeuLAsOG7eQe2DHoWemBBdcS3Lh3B
+uHc5To1/EWGR5sYqgeMnP9KrghLLdHMTD4e/Xl2SiSFhSG1YnCivJnhkLSlaXUUEfNmimqLYPBe3uC1p
+cSdU4nVWlaWQGlKy3k1Vr9dbwzdX/ybs41qcmwDpUewH7llx9rGCEzHDeuIP4FuM2Ci/JpuM86N3J+VX
+Uq1B8ji3oVv5mdW5NcLJn0ECddfAJg2FlhWMCmNS2HW/vA173BRLssKdz2uWMBLukrxS7BNcxVgcqFq8
+OkT7YFwXTCm00N/mhoxF9pWjIzVhPLgdEQYups3bDLA44Imo0LEw1Jq6tXnXqExUGNp4T5skwFJLQxVZ
+8SrPVwtyxoXG1wIo4PpsQTCbna1zyj+cuVtdDfIB3hFvNp57+jG+NSzfJ/t4V+lex/hsEgUu7ljx36Kw
+ep17cEsvw2iG7bAMoSfo0qu/poiga8ifzHw2BTYw6cRiBB/hsBWSRUPs5YFo7AGwwXSgMYZAm6q2FBib
+66b2Vt3ie1EXLDTPd8SqCtLPGjMj+XiKAwccM1Bo98pqn+N+mdg8wcn/u6H7LoAfs9uY7fz2+9/kgzfS
+3niT0gJpiOPp4Z8MMPpdCjhsZOtq4RgEa2hPcdp7r2P9NFor3CDR8YWChPqWJyuzyyoopC/+IrEXjLuo
+H4Qrpg3N1bgOLg3Vk5Rg9/lqtZBCwgqav8yojDa1LBUxZDTRg6bH8GZzUmDVKFTTS8K3a6WwKNSipR5K
+agmIbUXYpnhdWbq+y6NfulspqnLkXL8ampOdyu5IUrZl+Pw93UHWGPGvXQ8qfJKfTVp47etUxdJKme0U
+S1D5WLNrMK/2po/vHupFpXT9vHeViG2h6N5T//FpKIWcFUxD3PcamvFOxAM/Z5M1VkrMnMDODh5tABNy
+Ix0dE63kmC5n3cS00Ww+jpDzie3MYNsy1OcM9hXDvctIBzR8s876v/aeQdEV1UnmnRa5ARFxBLGR1S3d
+9aZ0bqWJoIKWJZhmIIazsQ8ehNZP3IF1QnYZWsT057ujG6wAad4GqGqNr4AuBGu6HqykWOXhwlmvGsRj
+bmiVmzZpvVdHdaXEDCt3v8MuMOezaGLwwfkaqZmY/qlJes34yg598KFCObFXmXHmA0Yt2slSNd1818zB
+hwxLMeuwzY4ATTIiMc20x2EtXQsOvZfN/WxCKLcC2Us/vH2Dd6+nAzXIEr5KIpxkBg6/7FjN7YdG8U9H
+31o0sfiw1WxeaUxSFyfG0R1zvLW29jY44Vg0oNmAXqNa9eo0bGt/2hAS2JYH1did3jriuFKDK06vxOkz
+3013PF7TbvCVwJOjh8GDmXwwCEcf2RMbx1+kYfzZG4MTX40HuwSzhX8IEmkctP7YI9gtGB2iuIvuNJOA
+gA9F/cjvCRPf+t42wgi3kG9N8K/JPjbqZyfuZSyjXrk/ouIKzRRGpwlfxxzB0w2dWgM85S5fnHq343OB
+p314XCoINwuPzgPhLt4YqyP8ti6cZub/fvYvCnWkjuwnAAA=
 `,
 	},
 
@@ -4,15 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/frictionlessdata/datapackage-go/validator/profile_cache"
 	"github.com/santhosh-tekuri/jsonschema"
 
-	_ "github.com/santhosh-tekuri/jsonschema/httploader" // This import alows jsonschema to load urls.
-	_ "github.com/santhosh-tekuri/jsonschema/loader"     // This import alows jsonschema to load filepaths.
+	_ "github.com/santhosh-tekuri/jsonschema/loader" // This import alows jsonschema to load filepaths.
 )
 
 // RegistryLoader loads a registry.
@@ -90,17 +88,13 @@ func (remote *remoteRegistry) GetValidator(profile string) (DescriptorValidator,
 }
 
 // RemoteRegistryLoader loads the schema registry map from the passed-in URL.
+// If the offline mode is enabled, the registry is only loaded from the on-disk profile cache.
 func RemoteRegistryLoader(url string) RegistryLoader {
 	return func() (Registry, error) {
-		resp, err := http.Get(url)
+		buf, err := fetchRemote(url)
 		if err != nil {
 			return nil, fmt.Errorf("error fetching remote profile cache registry from %s: %q", url, err)
 		}
-		defer resp.Body.Close()
-		buf, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("error reading remote profile cache registry from %s: %q", url, err)
-		}
 		m, err := unmarshalRegistryContents(buf)
 		if err != nil {
 			return nil, err
@@ -138,6 +132,98 @@ func FallbackRegistryLoader(loaders ...RegistryLoader) RegistryLoader {
 	}
 }
 
+// MapRegistry is an in-memory registry where profiles are registered programmatically.
+// It is safe for concurrent use.
+type MapRegistry struct {
+	mu       sync.RWMutex
+	profiles map[string]DescriptorValidator
+}
+
+// NewMapRegistry creates an empty MapRegistry.
+func NewMapRegistry() *MapRegistry {
+	return &MapRegistry{profiles: make(map[string]DescriptorValidator)}
+}
+
+// Register compiles the passed-in JSONSchema and makes it available under the profile id.
+// Registering an existing id replaces the previous profile.
+func (m *MapRegistry) Register(id string, schema []byte) error {
+	if id == "" {
+		return fmt.Errorf("profile id must not be empty")
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(id, bytes.NewReader(schema)); err != nil {
+		return fmt.Errorf("error parsing profile %s: %w", id, err)
+	}
+	s, err := c.Compile(id)
+	if err != nil {
+		return fmt.Errorf("error compiling profile %s: %w", id, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.profiles[id] = &jsonSchema{schema: s}
+	return nil
+}
+
+// GetValidator returns the validator registered under the passed-in profile id.
+func (m *MapRegistry) GetValidator(profile string) (DescriptorValidator, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("invalid profile:%s", profile)
+	}
+	return v, nil
+}
+
+// MapRegistryLoader returns a loader which always returns the passed-in registry.
+func MapRegistryLoader(m *MapRegistry) RegistryLoader {
+	return func() (Registry, error) {
+		return m, nil
+	}
+}
+
+type chainRegistry struct {
+	registries []Registry
+}
+
+func (chain *chainRegistry) GetValidator(profile string) (DescriptorValidator, error) {
+	var errs []string
+	for _, r := range chain.registries {
+		v, err := r.GetValidator(profile)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, fmt.Errorf("invalid profile:%s (%s)", profile, strings.Join(errs, "; "))
+}
+
+// ChainRegistry layers the passed-in registries. Profiles are looked up in order and
+// the first registry able to return a validator wins, which allows custom profiles
+// to be layered over (or override) the built-in ones.
+func ChainRegistry(registries ...Registry) Registry {
+	return &chainRegistry{registries: registries}
+}
+
+// ChainRegistryLoader loads all passed-in registries and chains them (see ChainRegistry).
+// It returns an error if any of the loaders fails.
+func ChainRegistryLoader(loaders ...RegistryLoader) RegistryLoader {
+	return func() (Registry, error) {
+		if len(loaders) == 0 {
+			return nil, fmt.Errorf("there should be at least one registry loader to chain")
+		}
+		registries := make([]Registry, len(loaders))
+		for i, loader := range loaders {
+			reg, err := loader()
+			if err != nil {
+				return nil, err
+			}
+			registries[i] = reg
+		}
+		return ChainRegistry(registries...), nil
+	}
+}
+
 func unmarshalRegistryContents(buf []byte) (map[string]profileSpec, error) {
 	var specs []profileSpec
 	if err := json.Unmarshal(buf, &specs); err != nil {
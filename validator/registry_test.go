@@ -67,6 +67,10 @@ func TestNew(t *testing.T) {
 			"table-schema",
 			"tabular-data-package",
 			"tabular-data-resource",
+			"data-package-v2",
+			"data-resource-v2",
+			"table-schema-v2",
+			"table-dialect-v2",
 		}
 		loader, err := localLoader()
 		is.NoErr(err)
@@ -138,6 +142,65 @@ func TestFallbackRegistryLoader(t *testing.T) {
 	})
 }
 
+func TestMapRegistry(t *testing.T) {
+	t.Run("Register", func(t *testing.T) {
+		is := is.New(t)
+		reg := NewMapRegistry()
+		is.NoErr(reg.Register("my-profile", []byte(simpleSchema)))
+		v, err := reg.GetValidator("my-profile")
+		is.NoErr(err)
+		is.NoErr(v.Validate(map[string]interface{}{"name": "foo"}))
+		is.True(v.Validate(map[string]interface{}{}) != nil)
+	})
+	t.Run("InvalidSchema", func(t *testing.T) {
+		reg := NewMapRegistry()
+		if err := reg.Register("my-profile", []byte(`{`)); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if err := reg.Register("", []byte(simpleSchema)); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+	t.Run("UnknownProfile", func(t *testing.T) {
+		if _, err := NewMapRegistry().GetValidator("foo"); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
+
+func TestChainRegistry(t *testing.T) {
+	custom := NewMapRegistry()
+	if err := custom.Register("my-profile", []byte(simpleSchema)); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("LayeredOverBuiltIn", func(t *testing.T) {
+		is := is.New(t)
+		reg, err := NewRegistry(ChainRegistryLoader(MapRegistryLoader(custom), InMemoryLoader()))
+		is.NoErr(err)
+		_, err = reg.GetValidator("my-profile")
+		is.NoErr(err)
+		_, err = reg.GetValidator("data-package")
+		is.NoErr(err)
+		_, err = reg.GetValidator("foo")
+		is.True(err != nil)
+	})
+	t.Run("FirstWins", func(t *testing.T) {
+		is := is.New(t)
+		reg := ChainRegistry(&neverValidRegistry{}, custom)
+		v, err := reg.GetValidator("my-profile")
+		is.NoErr(err)
+		is.True(v.Validate(map[string]interface{}{"name": "foo"}) != nil)
+	})
+	t.Run("LoaderError", func(t *testing.T) {
+		if _, err := ChainRegistryLoader(InMemoryLoader(), RemoteRegistryLoader("http://127.0.0.1/bar"))(); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+		if _, err := ChainRegistryLoader()(); err == nil {
+			t.Fatalf("want:err got:nil")
+		}
+	})
+}
+
 func serverForTests(contents string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintln(w, contents)
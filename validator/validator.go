@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/santhosh-tekuri/jsonschema"
 )
@@ -16,14 +17,19 @@ const localRegistryPath = "/registry.json"
 const remoteRegistryURL = "http://frictionlessdata.io/schemas/registry.json"
 
 // NewRegistry returns a registry where users could get descriptor validators.
+// If no loader is passed, the in-memory cache is tried first, followed by the local
+// file system and the remote registry. The remote registry is left out when
+// the offline mode is enabled.
 func NewRegistry(loaders ...RegistryLoader) (Registry, error) {
 	// Default settings.
 	if len(loaders) == 0 {
 		loaders = append(
 			loaders,
 			InMemoryLoader(),
-			LocalRegistryLoader(localRegistryPath, false /* inMemoryOnly*/),
-			RemoteRegistryLoader(remoteRegistryURL))
+			LocalRegistryLoader(localRegistryPath, false /* inMemoryOnly*/))
+		if !Offline() {
+			loaders = append(loaders, RemoteRegistryLoader(remoteRegistryURL))
+		}
 	}
 	registry, err := FallbackRegistryLoader(loaders...)()
 	if err != nil {
@@ -32,15 +38,46 @@ func NewRegistry(loaders ...RegistryLoader) (Registry, error) {
 	return registry, nil
 }
 
-// New returns a new descriptor validator for the passed-in profile.
+// Compiled remote profiles, indexed by URL. Avoids fetching the same
+// third-party schema over and over again. It is bypassed while the offline mode or
+// the on-disk profile cache are enabled (see memoryCacheEnabled).
+var remoteProfiles sync.Map
+
+// memoryCacheEnabled returns whether compiled remote profiles can be served from memory.
+// The offline mode and the on-disk profile cache decide where remote profiles come from,
+// so remote profiles are always loaded through fetchRemote while any of them is enabled.
+func memoryCacheEnabled() bool {
+	offlineMu.RLock()
+	defer offlineMu.RUnlock()
+	return !offline && profileCacheDir == ""
+}
+
+// IsThirdPartyProfile returns whether the passed-in profile is directly referenced
+// by URL (http, https or file), instead of being a registry identifier.
+func IsThirdPartyProfile(profile string) bool {
+	return strings.HasPrefix(profile, "http") || strings.HasPrefix(profile, "file")
+}
+
+// New returns a new descriptor validator for the passed-in profile. Remote profiles
+// are subject to the offline mode and the on-disk profile cache (see SetOffline).
 func New(profile string, loaders ...RegistryLoader) (DescriptorValidator, error) {
 	// If it is a third-party schema. Directly referenced from the internet or local file.
-	if strings.HasPrefix(profile, "http") || strings.HasPrefix(profile, "file") {
+	if IsThirdPartyProfile(profile) {
+		remote := strings.HasPrefix(profile, "http") && memoryCacheEnabled()
+		if remote {
+			if v, ok := remoteProfiles.Load(profile); ok {
+				return v.(DescriptorValidator), nil
+			}
+		}
 		schema, err := jsonschema.Compile(profile)
 		if err != nil {
 			return nil, err
 		}
-		return &jsonSchema{schema: schema}, nil
+		v := &jsonSchema{schema: schema}
+		if remote {
+			remoteProfiles.Store(profile, v)
+		}
+		return v, nil
 	}
 	registry, err := NewRegistry(loaders...)
 	if err != nil {
@@ -14,3 +14,15 @@ func ExampleNewRegistry() {
 	fmt.Println(validator.Validate(map[string]interface{}{"name": "res1", "path": "foo.csv"}))
 	// Output: <nil>
 }
+
+func ExampleChainRegistry() {
+	// Schemas could also be shipped within the binary using the embed package.
+	custom := NewMapRegistry()
+	custom.Register("my-profile", []byte(`{"type": "object", "required": ["title"]}`))
+	registry := ChainRegistry(custom, MustInMemoryRegistry())
+
+	fmt.Println(Validate(map[string]interface{}{"title": "foo"}, "my-profile", registry))
+	fmt.Println(Validate(map[string]interface{}{"name": "foo", "path": "foo.csv"}, "data-resource", registry))
+	// Output: <nil>
+	// <nil>
+}